@@ -0,0 +1,4 @@
+CREATE TABLE dbtxn_decision (
+    gid VARCHAR (64) PRIMARY KEY,
+    decided_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
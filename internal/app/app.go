@@ -14,6 +14,7 @@ import (
 	"github.com/typical-go/typical-go/pkg/errkit"
 	"github.com/typical-go/typical-rest-server/internal/app/infra"
 	"github.com/typical-go/typical-rest-server/pkg/cachekit"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"github.com/typical-go/typical-rest-server/pkg/echokit"
 	"go.uber.org/dig"
 
@@ -30,6 +31,9 @@ func Start(
 	cfg *infra.AppCfg,
 	e *echo.Echo,
 ) (err error) {
+	if err := di.Invoke(RecoverTransactions); err != nil {
+		return err
+	}
 	if err := di.Invoke(SetServer); err != nil {
 		return err
 	}
@@ -47,10 +51,30 @@ func Start(
 	})
 }
 
+// RecoverTransactions resolve orphaned prepared transactions of two-phase
+// commit by the commit decision of coordinator at startup and periodically
+func RecoverTransactions(p struct {
+	dig.In
+	Pg *sql.DB `name:"pg"`
+	// MySQL *sql.DB `name:"mysql"`
+}) error {
+	coordinator := &dbtxn.Coordinator{DB: p.Pg}
+	gids, err := coordinator.Recover(context.Background(), p.Pg)
+	if len(gids) > 0 {
+		logrus.Warnf("Resolve orphaned prepared transactions: %s", strings.Join(gids, ", "))
+	}
+	if err != nil {
+		return err
+	}
+	go coordinator.Run(context.Background(), p.Pg)
+	return nil
+}
+
 // Shutdown infra
 func Shutdown(p struct {
 	dig.In
-	Pg *sql.DB `name:"pg"`
+	Pg         *sql.DB   `name:"pg"`
+	PgReplicas []*sql.DB `name:"pg_replicas"`
 	// MySQL *sql.DB `name:"mysql"`
 	Cache *cachekit.Store
 	Echo  *echo.Echo
@@ -66,6 +90,9 @@ func Shutdown(p struct {
 		p.Cache.Close(),
 		p.Echo.Shutdown(ctx),
 	}
+	for _, db := range p.PgReplicas {
+		errs = append(errs, db.Close())
+	}
 
 	return errs.Unwrap()
 }
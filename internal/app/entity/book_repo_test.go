@@ -0,0 +1,180 @@
+package entity_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/internal/app/entity"
+	"github.com/typical-go/typical-rest-server/internal/generated/dbrepo"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestBookRepo_Find_Join(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := &dbrepo.BookRepoImpl{DB: db}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT books.id, books.title, books.author, books.updated_at, books.created_at, p.name AS p_name FROM books JOIN publishers p ON p.id = books.publisher_id WHERE books.id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "updated_at", "created_at", "p_name"}).
+			AddRow(1, "some-title", "some-author", now, now, []byte("some-publisher")))
+
+	join := &sqkit.Join{Table: "publishers", Alias: "p", On: "p.id = books.publisher_id", Columns: []string{"name"}}
+	books, err := repo.Find(context.Background(), join, sqkit.Eq{"books.id": 1})
+	require.NoError(t, err)
+	require.Equal(t, []*entity.Book{
+		{ID: 1, Title: "some-title", Author: "some-author", UpdatedAt: now, CreatedAt: now},
+	}, books)
+	require.Equal(t, []map[string]interface{}{{"p_name": "some-publisher"}}, join.Rows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookRepo_CountExists_Join(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := &dbrepo.BookRepoImpl{DB: db}
+	newJoin := func() *sqkit.Join {
+		return &sqkit.Join{Table: "publishers", Alias: "p", On: "p.id = books.publisher_id", Columns: []string{"name"}}
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM books JOIN publishers p ON p.id = books.publisher_id WHERE p.name = $1`)).
+		WithArgs("some-publisher").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM books JOIN publishers p ON p.id = books.publisher_id WHERE p.name = $1 LIMIT 1`)).
+		WithArgs("some-publisher").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	cnt, err := repo.Count(context.Background(), newJoin(), sqkit.Eq{"p.name": "some-publisher"})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), cnt)
+
+	exists, err := repo.Exists(context.Background(), newJoin(), sqkit.Eq{"p.name": "some-publisher"})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookRepo_Find_Lock(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := &dbrepo.BookRepoImpl{DB: db}
+	lock := &sqkit.Lock{Dialect: sqkit.Postgres, Wait: sqkit.SkipLocked}
+
+	_, err := repo.Find(context.Background(), lock)
+	require.Equal(t, sqkit.ErrLockWithoutTxn, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT books.id, books.title, books.author, books.updated_at, books.created_at FROM books FOR UPDATE SKIP LOCKED`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	ctx := context.Background()
+	txn := dbtxn.Begin(&ctx)
+	books, err := repo.Find(ctx, lock)
+	require.NoError(t, err)
+	require.Equal(t, []*entity.Book{{ID: 1}}, books)
+	require.NoError(t, txn.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookRepo_Projection(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := &dbrepo.BookRepoImpl{DB: db}
+	projection := sqkit.Projection{"id", "title"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT books.id, books.title FROM books WHERE id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "some-title"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM books WHERE id = $1 LIMIT 1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	books, err := repo.Find(context.Background(), projection, sqkit.Eq{"id": 1})
+	require.NoError(t, err)
+	require.Equal(t, []*entity.Book{{ID: 1, Title: "some-title"}}, books)
+
+	exists, err := repo.Exists(context.Background(), projection, sqkit.Eq{"id": 1})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookRepo_FindOne_NotModifyOptions(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	repo := &dbrepo.BookRepoImpl{DB: db}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT books.id, books.title, books.author, books.updated_at, books.created_at FROM books WHERE id = $1 LIMIT 1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "some-title"))
+
+	opts := make([]sqkit.SelectOption, 1, 2)
+	opts[0] = sqkit.Eq{"id": 1}
+	book, err := repo.FindOne(context.Background(), opts...)
+	require.NoError(t, err)
+	require.Equal(t, &entity.Book{ID: 1, Title: "some-title"}, book)
+	require.Nil(t, opts[:2][1])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookRepo_Insert_OnConflict(t *testing.T) {
+	testcases := []struct {
+		testName    string
+		mockFn      func(sqlmock.Sqlmock)
+		expected    int64
+		expectedErr string
+	}{
+		{
+			testName: "inserted",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO books (title,author,updated_at,created_at) VALUES ($1,$2,$3,$4) ON CONFLICT (title) DO NOTHING RETURNING "id"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectCommit()
+			},
+			expected: 10,
+		},
+		{
+			testName: "do nothing on conflict",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO books`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectCommit()
+			},
+			expected: 0,
+		},
+		{
+			testName: "insert error",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO books`)).
+					WillReturnError(errors.New("insert-error"))
+				mock.ExpectRollback()
+			},
+			expected:    -1,
+			expectedErr: "insert-error",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectBegin()
+			tt.mockFn(mock)
+			repo := &dbrepo.BookRepoImpl{DB: db}
+
+			ctx := context.Background()
+			txn := dbtxn.Begin(&ctx)
+			id, err := repo.Insert(ctx, &entity.Book{Title: "some-title", Author: "some-author"},
+				&sqkit.OnConflict{Columns: []string{"title"}})
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expected, id)
+			txn.Commit()
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
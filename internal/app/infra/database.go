@@ -3,9 +3,11 @@ package infra
 import (
 	"database/sql"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"go.uber.org/dig"
 
 	// postgres driver
@@ -18,7 +20,8 @@ type (
 	// Databases setup output
 	Databases struct {
 		dig.Out
-		Pg *sql.DB `name:"pg"`
+		Pg         *sql.DB   `name:"pg"`
+		PgReplicas []*sql.DB `name:"pg_replicas"`
 		// MySQL *sql.DB `name:"mysql"`
 	}
 	DatabaseCfgs struct {
@@ -36,6 +39,9 @@ type (
 		Host   string `envconfig:"HOST" required:"true" default:"localhost"`
 		Port   string `envconfig:"PORT" required:"true" default:"9999"`
 
+		ReplicaHosts []string `envconfig:"REPLICA_HOSTS"`                  // host:port of read replicas, e.g. `replica1:5432,replica2:5432`
+		Balancer     string   `envconfig:"BALANCER" default:"round_robin"` // round_robin or least_conn
+
 		MaxOpenConns    int           `envconfig:"MAX_OPEN_CONNS" default:"30" required:"true"`
 		MaxIdleConns    int           `envconfig:"MAX_IDLE_CONNS" default:"6" required:"true"`
 		ConnMaxLifetime time.Duration `envconfig:"CONN_MAX_LIFETIME" default:"30m" required:"true"`
@@ -45,12 +51,31 @@ type (
 // NewDatabases return new instance of databases
 // @ctor
 func NewDatabases(cfgs DatabaseCfgs) Databases {
+	pg := openPostgres(cfgs.Pg, cfgs.Pg.Host, cfgs.Pg.Port)
+	var pgReplicas []*sql.DB
+	for _, hostPort := range cfgs.Pg.ReplicaHosts {
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			logrus.Fatalf("postgres: %s", err.Error())
+		}
+		pgReplicas = append(pgReplicas, openPostgres(cfgs.Pg, host, port))
+	}
+	dbtxn.SetReplicas(pg, balancer(cfgs.Pg.Balancer), pgReplicas...)
+
 	return Databases{
-		Pg: openPostgres(cfgs.Pg),
+		Pg:         pg,
+		PgReplicas: pgReplicas,
 		// MySQL: openMySQL(cfgs.Mysql),
 	}
 }
 
+func balancer(s string) dbtxn.Balancer {
+	if s == "least_conn" {
+		return dbtxn.LeastConn
+	}
+	return dbtxn.RoundRobin
+}
+
 // func openMySQL(p *DatabaseCfg) *sql.DB {
 // 	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?tls=false&parseTime=true",
 // 		p.DBUser, p.DBPass, p.Host, p.Port, p.DBName))
@@ -66,10 +91,10 @@ func NewDatabases(cfgs DatabaseCfgs) Databases {
 // 	return db
 // }
 
-func openPostgres(p *DatabaseCfg) *sql.DB {
+func openPostgres(p *DatabaseCfg, host, port string) *sql.DB {
 	conn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		p.DBUser, p.DBPass, p.Host, p.Port, p.DBName,
+		p.DBUser, p.DBPass, host, port, p.DBName,
 	)
 	db, err := sql.Open("postgres", conn)
 	if err != nil {
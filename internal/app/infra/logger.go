@@ -57,7 +57,7 @@ func LogMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 
 		stop := time.Now()
 		if _debug {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithFields(logruskit.GetFields(ctx)).WithFields(logrus.Fields{
 				"exec_time":   stop.Sub(start).String(),
 				"req_id":      reqID,
 				"resp_status": res.Status,
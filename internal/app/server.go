@@ -5,6 +5,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/typical-go/typical-rest-server/internal/app/controller"
 	"github.com/typical-go/typical-rest-server/internal/app/infra"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"github.com/typical-go/typical-rest-server/pkg/echokit"
 )
 
@@ -17,6 +18,7 @@ func SetServer(
 	// set middleware
 	e.Use(infra.LogMiddleware)
 	e.Use(middleware.Recover())
+	e.Use((&dbtxn.RequestScope{}).Middleware)
 
 	// set route
 	echokit.SetRoute(e,
@@ -5,11 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 
-	"github.com/labstack/echo/v4"
 	"github.com/typical-go/typical-rest-server/internal/app/entity"
 	"github.com/typical-go/typical-rest-server/internal/generated/dbrepo"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 	"github.com/typical-go/typical-rest-server/pkg/echokit"
 	"github.com/typical-go/typical-rest-server/pkg/sqkit"
 	"go.uber.org/dig"
@@ -36,6 +35,7 @@ type (
 		Limit  uint64 `query:"limit"`
 		Offset uint64 `query:"offset"`
 		Sort   string `query:"sort"`
+		Fields string `query:"fields"`
 	}
 	// FindBookResp find book resp
 	FindBookResp struct {
@@ -44,6 +44,8 @@ type (
 	}
 )
 
+var bookColumns = sqkit.ColumnsOf(dbrepo.BookTable)
+
 // NewBookSvc return new instance of BookSvc
 // @ctor
 func NewBookSvc(impl BookSvcImpl) BookSvc {
@@ -59,7 +61,8 @@ func (b *BookSvcImpl) Create(ctx context.Context, book *entity.Book) (*entity.Bo
 	if err != nil {
 		return nil, err
 	}
-	return b.findOne(ctx, id)
+	dbtxn.ForcePrimary(&ctx) // NOTE: read-after-write
+	return b.Repo.FindByID(ctx, id)
 }
 
 func (b *BookSvcImpl) validateBook(book *entity.Book) string {
@@ -77,7 +80,18 @@ func (b *BookSvcImpl) Find(ctx context.Context, req *FindBookReq) (*FindBookResp
 	var opts []sqkit.SelectOption
 	opts = append(opts, &sqkit.OffsetPagination{Offset: req.Offset, Limit: req.Limit})
 	if req.Sort != "" {
-		opts = append(opts, sqkit.Sorts(strings.Split(req.Sort, ",")))
+		sorts, err := sqkit.ParseSorts(req.Sort, bookColumns, sqkit.Postgres)
+		if err != nil {
+			return nil, echokit.NewValidErr(err.Error())
+		}
+		opts = append(opts, sorts)
+	}
+	if req.Fields != "" {
+		projection, err := sqkit.ParseProjection(req.Fields, bookColumns)
+		if err != nil {
+			return nil, echokit.NewValidErr(err.Error())
+		}
+		opts = append(opts, projection)
 	}
 	totalCount, err := b.Repo.Count(ctx)
 	if err != nil {
@@ -96,17 +110,7 @@ func (b *BookSvcImpl) Find(ctx context.Context, req *FindBookReq) (*FindBookResp
 // FindOne book
 func (b *BookSvcImpl) FindOne(ctx context.Context, paramID string) (*entity.Book, error) {
 	id, _ := strconv.ParseInt(paramID, 10, 64)
-	return b.findOne(ctx, id)
-}
-
-func (b *BookSvcImpl) findOne(ctx context.Context, id int64) (*entity.Book, error) {
-	books, err := b.Repo.Find(ctx, sqkit.Eq{dbrepo.BookTable.ID: id})
-	if err != nil {
-		return nil, err
-	} else if len(books) < 1 {
-		return nil, echo.ErrNotFound
-	}
-	return books[0], nil
+	return b.Repo.FindByID(ctx, id)
 }
 
 // Delete book
@@ -122,13 +126,14 @@ func (b *BookSvcImpl) Update(ctx context.Context, paramID string, book *entity.B
 	if errMsg := b.validateBook(book); errMsg != "" {
 		return nil, echokit.NewValidErr(errMsg)
 	}
-	if _, err := b.findOne(ctx, id); err != nil {
+	if _, err := b.Repo.FindByID(ctx, id); err != nil {
 		return nil, err
 	}
 	if err := b.update(ctx, id, book); err != nil {
 		return nil, err
 	}
-	return b.findOne(ctx, id)
+	dbtxn.ForcePrimary(&ctx) // NOTE: read-after-write
+	return b.Repo.FindByID(ctx, id)
 }
 
 func (b *BookSvcImpl) update(ctx context.Context, id int64, book *entity.Book) error {
@@ -145,13 +150,14 @@ func (b *BookSvcImpl) update(ctx context.Context, id int64, book *entity.Book) e
 // Patch book
 func (b *BookSvcImpl) Patch(ctx context.Context, paramID string, book *entity.Book) (*entity.Book, error) {
 	id, _ := strconv.ParseInt(paramID, 10, 64)
-	if _, err := b.findOne(ctx, id); err != nil {
+	if _, err := b.Repo.FindByID(ctx, id); err != nil {
 		return nil, err
 	}
 	if err := b.patch(ctx, id, book); err != nil {
 		return nil, err
 	}
-	return b.findOne(ctx, id)
+	dbtxn.ForcePrimary(&ctx) // NOTE: read-after-write
+	return b.Repo.FindByID(ctx, id)
 }
 
 func (b *BookSvcImpl) patch(ctx context.Context, id int64, book *entity.Book) error {
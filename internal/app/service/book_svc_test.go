@@ -66,7 +66,7 @@ func TestBookSvc_Create(t *testing.T) {
 					Insert(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}).
 					Return(int64(1), nil)
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
+					FindByID(gomock.Any(), int64(1)).
 					Return(nil, errors.New("find-error"))
 			},
 		},
@@ -81,8 +81,8 @@ func TestBookSvc_Create(t *testing.T) {
 					Insert(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}).
 					Return(int64(1), nil)
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{Author: "some-author", Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{Author: "some-author", Title: "some-title"}, nil)
 			},
 		},
 	}
@@ -115,7 +115,7 @@ func TestBookSvc_FindOne(t *testing.T) {
 			paramID: "1",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
+					FindByID(gomock.Any(), int64(1)).
 					Return(nil, errors.New("some-error"))
 			},
 			expectedErr: "some-error",
@@ -124,8 +124,8 @@ func TestBookSvc_FindOne(t *testing.T) {
 			paramID: "1",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 			},
 			expected: &entity.Book{ID: 1, Title: "some-title"},
 		},
@@ -133,10 +133,10 @@ func TestBookSvc_FindOne(t *testing.T) {
 			paramID: "1",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(nil, &sqkit.NotFoundError{Table: dbrepo.BookTableName})
 			},
-			expectedErr: "code=404, message=Not Found",
+			expectedErr: "books not found",
 		},
 	}
 	for _, tt := range testcases {
@@ -199,12 +199,41 @@ func TestBookSvc_Find(t *testing.T) {
 					Count(gomock.Any()).
 					Return(int64(10), nil)
 				mockRepo.EXPECT().
-					Find(gomock.Any(), &sqkit.OffsetPagination{Limit: 20, Offset: 10}, sqkit.Sorts{"title", "created_at"}).
+					Find(gomock.Any(), &sqkit.OffsetPagination{Limit: 20, Offset: 10}, sqkit.Sorts{
+						{Column: "title", Dialect: sqkit.Postgres},
+						{Column: "created_at", Desc: true, Dialect: sqkit.Postgres},
+					}).
 					Return(nil, errors.New("find-error"))
 			},
-			req:         &service.FindBookReq{Limit: 20, Offset: 10, Sort: "title,created_at"},
+			req:         &service.FindBookReq{Limit: 20, Offset: 10, Sort: "title,-created_at"},
 			expectedErr: "find-error",
 		},
+		{
+			testName: "with fields",
+			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
+				mockRepo.EXPECT().
+					Count(gomock.Any()).
+					Return(int64(10), nil)
+				mockRepo.EXPECT().
+					Find(gomock.Any(), &sqkit.OffsetPagination{Limit: 20}, sqkit.Projection{"id", "title"}).
+					Return([]*entity.Book{{ID: 1, Title: "title1"}}, nil)
+			},
+			req: &service.FindBookReq{Limit: 20, Fields: "id,title"},
+			expected: &service.FindBookResp{
+				Books:      []*entity.Book{{ID: 1, Title: "title1"}},
+				TotalCount: "10",
+			},
+		},
+		{
+			testName:    "unknown field",
+			req:         &service.FindBookReq{Fields: "id,password"},
+			expectedErr: "code=422, message=sqkit: unknown field 'password'",
+		},
+		{
+			testName:    "unknown sort column",
+			req:         &service.FindBookReq{Sort: "title,1;DROP TABLE books"},
+			expectedErr: "code=422, message=sqkit: unknown sort column '1;DROP TABLE books'",
+		},
 	}
 	for _, tt := range testcases {
 		t.Run(tt.testName, func(t *testing.T) {
@@ -299,8 +328,8 @@ func TestBookSvc_Update(t *testing.T) {
 			expectedErr: "update error",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 				mockRepo.EXPECT().
 					Update(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}, sqkit.Eq{"id": int64(1)}).
 					Return(int64(-1), errors.New("update error"))
@@ -313,8 +342,8 @@ func TestBookSvc_Update(t *testing.T) {
 			expectedErr: "no affected row",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 				mockRepo.EXPECT().
 					Update(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}, sqkit.Eq{"id": int64(1)}).
 					Return(int64(0), nil)
@@ -327,7 +356,7 @@ func TestBookSvc_Update(t *testing.T) {
 			expectedErr: "find-error",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
+					FindByID(gomock.Any(), int64(1)).
 					Return(nil, errors.New("find-error"))
 			},
 		},
@@ -338,13 +367,13 @@ func TestBookSvc_Update(t *testing.T) {
 			expectedErr: "find-error",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 				mockRepo.EXPECT().
 					Update(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}, sqkit.Eq{"id": int64(1)}).
 					Return(int64(1), nil)
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
+					FindByID(gomock.Any(), int64(1)).
 					Return(nil, errors.New("find-error"))
 			},
 		},
@@ -381,8 +410,8 @@ func TestBookSvc_Patch(t *testing.T) {
 			expectedErr: "patch-error",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 				mockRepo.EXPECT().
 					Patch(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}, sqkit.Eq{"id": int64(1)}).
 					Return(int64(-1), errors.New("patch-error"))
@@ -395,8 +424,8 @@ func TestBookSvc_Patch(t *testing.T) {
 			expectedErr: "no affected row",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 				mockRepo.EXPECT().
 					Patch(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}, sqkit.Eq{"id": int64(1)}).
 					Return(int64(0), nil)
@@ -409,7 +438,7 @@ func TestBookSvc_Patch(t *testing.T) {
 			expectedErr: "find-error",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
+					FindByID(gomock.Any(), int64(1)).
 					Return(nil, errors.New("find-error"))
 			},
 		},
@@ -420,13 +449,13 @@ func TestBookSvc_Patch(t *testing.T) {
 			expectedErr: "find-error",
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 				mockRepo.EXPECT().
 					Patch(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}, sqkit.Eq{"id": int64(1)}).
 					Return(int64(1), nil)
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
+					FindByID(gomock.Any(), int64(1)).
 					Return(nil, errors.New("find-error"))
 			},
 		},
@@ -436,14 +465,14 @@ func TestBookSvc_Patch(t *testing.T) {
 			expected: &entity.Book{Author: "some-author", Title: "some-title"},
 			bookSvcFn: func(mockRepo *dbrepo.MockBookRepo) {
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{ID: 1, Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{ID: 1, Title: "some-title"}, nil)
 				mockRepo.EXPECT().
 					Patch(gomock.Any(), &entity.Book{Author: "some-author", Title: "some-title"}, sqkit.Eq{"id": int64(1)}).
 					Return(int64(1), nil)
 				mockRepo.EXPECT().
-					Find(gomock.Any(), sqkit.Eq{"id": int64(1)}).
-					Return([]*entity.Book{{Author: "some-author", Title: "some-title"}}, nil)
+					FindByID(gomock.Any(), int64(1)).
+					Return(&entity.Book{Author: "some-author", Title: "some-title"}, nil)
 			},
 		},
 	}
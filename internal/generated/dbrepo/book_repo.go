@@ -41,8 +41,11 @@ type (
 	BookRepo interface {
 		Count(context.Context, ...sqkit.SelectOption) (int64, error)
 		Find(context.Context, ...sqkit.SelectOption) ([]*entity.Book, error)
-		Insert(context.Context, *entity.Book) (int64, error)
-		BulkInsert(context.Context, ...*entity.Book) (int64, error)
+		FindOne(context.Context, ...sqkit.SelectOption) (*entity.Book, error)
+		FindByID(context.Context, int64) (*entity.Book, error)
+		Exists(context.Context, ...sqkit.SelectOption) (bool, error)
+		Insert(context.Context, *entity.Book, ...sqkit.InsertOption) (int64, error)
+		BulkInsert(context.Context, []*entity.Book, ...sqkit.InsertOption) (int64, error)
 		Delete(context.Context, sqkit.DeleteOption) (int64, error)
 		Update(context.Context, *entity.Book, sqkit.UpdateOption) (int64, error)
 		Patch(context.Context, *entity.Book, sqkit.UpdateOption) (int64, error)
@@ -60,13 +63,14 @@ func init() {
 
 // Count books
 func (r *BookRepoImpl) Count(ctx context.Context, opts ...sqkit.SelectOption) (int64, error) {
-	txn, err := dbtxn.Use(ctx, r.DB)
+	txn, err := dbtxn.UseRead(ctx, r.DB)
 	if err != nil {
 		return -1, err
 	}
 	builder := sq.
 		Select("count(*)").
 		From(BookTableName).
+		PlaceholderFormat(sq.Dollar).
 		RunWith(txn)
 
 	for _, opt := range opts {
@@ -89,18 +93,22 @@ func NewBookRepo(impl BookRepoImpl) BookRepo {
 
 // Find books
 func (r *BookRepoImpl) Find(ctx context.Context, opts ...sqkit.SelectOption) (list []*entity.Book, err error) {
-	txn, err := dbtxn.Use(ctx, r.DB)
+	if sqkit.HasLock(opts) && dbtxn.Find(ctx) == nil {
+		return nil, sqkit.ErrLockWithoutTxn
+	}
+	txn, err := dbtxn.UseRead(ctx, r.DB)
 	if err != nil {
 		return nil, err
 	}
 	builder := sq.
-		Select(
+		Select(sqkit.Qualify(BookTableName, sqkit.SelectColumns([]string{
 			BookTable.ID,
 			BookTable.Title,
 			BookTable.Author,
 			BookTable.UpdatedAt,
 			BookTable.CreatedAt,
-		).
+		}, opts)...)...).
+		Columns(sqkit.SelectExtra(opts)...).
 		From(BookTableName).
 		PlaceholderFormat(sq.Dollar).
 		RunWith(txn)
@@ -114,25 +122,90 @@ func (r *BookRepoImpl) Find(ctx context.Context, opts ...sqkit.SelectOption) (li
 		return
 	}
 
+	columns, err := rows.Columns()
+	if err != nil {
+		return
+	}
+
 	list = make([]*entity.Book, 0)
 	for rows.Next() {
 		ent := new(entity.Book)
-		if err = rows.Scan(
-			&ent.ID,
-			&ent.Title,
-			&ent.Author,
-			&ent.UpdatedAt,
-			&ent.CreatedAt,
-		); err != nil {
+		dest := make([]interface{}, len(columns))
+		extra := make(map[string]interface{})
+		for i, column := range columns {
+			switch column {
+			case BookTable.ID:
+				dest[i] = &ent.ID
+			case BookTable.Title:
+				dest[i] = &ent.Title
+			case BookTable.Author:
+				dest[i] = &ent.Author
+			case BookTable.UpdatedAt:
+				dest[i] = &ent.UpdatedAt
+			case BookTable.CreatedAt:
+				dest[i] = &ent.CreatedAt
+			default:
+				dest[i] = new(interface{})
+				extra[column] = dest[i]
+			}
+		}
+		if err = rows.Scan(dest...); err != nil {
 			return
 		}
+		sqkit.ScanExtra(opts, extra)
 		list = append(list, ent)
 	}
 	return
 }
 
-// Insert books and return last inserted id
-func (r *BookRepoImpl) Insert(ctx context.Context, ent *entity.Book) (int64, error) {
+// FindOne books or return sqkit.NotFoundError
+func (r *BookRepoImpl) FindOne(ctx context.Context, opts ...sqkit.SelectOption) (*entity.Book, error) {
+	opts = append(opts[:len(opts):len(opts)], &sqkit.OffsetPagination{Limit: 1}) // NOTE: copy to not modify the caller options
+	list, err := r.Find(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) < 1 {
+		return nil, &sqkit.NotFoundError{Table: BookTableName}
+	}
+	return list[0], nil
+}
+
+// FindByID books or return sqkit.NotFoundError
+func (r *BookRepoImpl) FindByID(ctx context.Context, id int64) (*entity.Book, error) {
+	return r.FindOne(ctx, sqkit.Eq{BookTable.ID: id})
+}
+
+// Exists return true if any books match the options
+func (r *BookRepoImpl) Exists(ctx context.Context, opts ...sqkit.SelectOption) (bool, error) {
+	txn, err := dbtxn.UseRead(ctx, r.DB)
+	if err != nil {
+		return false, err
+	}
+	builder := sq.
+		Select("1").
+		From(BookTableName).
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(txn)
+
+	for _, opt := range opts {
+		builder = opt.CompileSelect(builder)
+	}
+
+	var one int
+	if err := builder.QueryRowContext(ctx).Scan(&one); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Insert books and return last inserted id or 0 when nothing inserted
+// (e.g. by sqkit.OnConflict with DO NOTHING)
+func (r *BookRepoImpl) Insert(ctx context.Context, ent *entity.Book, opts ...sqkit.InsertOption) (int64, error) {
 	txn, err := dbtxn.Use(ctx, r.DB)
 	if err != nil {
 		return -1, err
@@ -146,9 +219,6 @@ func (r *BookRepoImpl) Insert(ctx context.Context, ent *entity.Book) (int64, err
 			BookTable.UpdatedAt,
 			BookTable.CreatedAt,
 		).
-		Suffix(
-			fmt.Sprintf("RETURNING \"%s\"", BookTable.ID),
-		).
 		PlaceholderFormat(sq.Dollar).
 		Values(
 			ent.Title,
@@ -157,10 +227,22 @@ func (r *BookRepoImpl) Insert(ctx context.Context, ent *entity.Book) (int64, err
 			time.Now(),
 		)
 
-	scanner := builder.RunWith(txn).QueryRowContext(ctx)
+	for _, opt := range opts {
+		builder = opt.CompileInsert(builder)
+	}
+
+	scanner := builder.
+		Suffix(
+			fmt.Sprintf("RETURNING \"%s\"", BookTable.ID),
+		).
+		RunWith(txn).
+		QueryRowContext(ctx)
 
 	var id int64
 	if err := scanner.Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil // NOTE: nothing inserted by ON CONFLICT DO NOTHING
+		}
 		txn.AppendError(err)
 		return -1, err
 	}
@@ -168,7 +250,7 @@ func (r *BookRepoImpl) Insert(ctx context.Context, ent *entity.Book) (int64, err
 }
 
 // BulkInsert books and return affected rows
-func (r *BookRepoImpl) BulkInsert(ctx context.Context, ents ...*entity.Book) (int64, error) {
+func (r *BookRepoImpl) BulkInsert(ctx context.Context, ents []*entity.Book, opts ...sqkit.InsertOption) (int64, error) {
 	txn, err := dbtxn.Use(ctx, r.DB)
 	if err != nil {
 		return -1, err
@@ -193,6 +275,10 @@ func (r *BookRepoImpl) BulkInsert(ctx context.Context, ents ...*entity.Book) (in
 		)
 	}
 
+	for _, opt := range opts {
+		builder = opt.CompileInsert(builder)
+	}
+
 	res, err := builder.RunWith(txn).ExecContext(ctx)
 	if err != nil {
 		txn.AppendError(err)
@@ -36,10 +36,10 @@ func (m *MockBookRepo) EXPECT() *MockBookRepoMockRecorder {
 }
 
 // BulkInsert mocks base method
-func (m *MockBookRepo) BulkInsert(arg0 context.Context, arg1 ...*entity.Book) (int64, error) {
+func (m *MockBookRepo) BulkInsert(arg0 context.Context, arg1 []*entity.Book, arg2 ...sqkit.InsertOption) (int64, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{arg0}
-	for _, a := range arg1 {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "BulkInsert", varargs...)
@@ -49,9 +49,9 @@ func (m *MockBookRepo) BulkInsert(arg0 context.Context, arg1 ...*entity.Book) (i
 }
 
 // BulkInsert indicates an expected call of BulkInsert
-func (mr *MockBookRepoMockRecorder) BulkInsert(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+func (mr *MockBookRepoMockRecorder) BulkInsert(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{arg0}, arg1...)
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkInsert", reflect.TypeOf((*MockBookRepo)(nil).BulkInsert), varargs...)
 }
 
@@ -90,6 +90,26 @@ func (mr *MockBookRepoMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBookRepo)(nil).Delete), arg0, arg1)
 }
 
+// Exists mocks base method
+func (m *MockBookRepo) Exists(arg0 context.Context, arg1 ...sqkit.SelectOption) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists
+func (mr *MockBookRepoMockRecorder) Exists(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockBookRepo)(nil).Exists), varargs...)
+}
+
 // Find mocks base method
 func (m *MockBookRepo) Find(arg0 context.Context, arg1 ...sqkit.SelectOption) ([]*entity.Book, error) {
 	m.ctrl.T.Helper()
@@ -110,19 +130,59 @@ func (mr *MockBookRepoMockRecorder) Find(arg0 interface{}, arg1 ...interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockBookRepo)(nil).Find), varargs...)
 }
 
+// FindByID mocks base method
+func (m *MockBookRepo) FindByID(arg0 context.Context, arg1 int64) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockBookRepoMockRecorder) FindByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockBookRepo)(nil).FindByID), arg0, arg1)
+}
+
+// FindOne mocks base method
+func (m *MockBookRepo) FindOne(arg0 context.Context, arg1 ...sqkit.SelectOption) (*entity.Book, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindOne", varargs...)
+	ret0, _ := ret[0].(*entity.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOne indicates an expected call of FindOne
+func (mr *MockBookRepoMockRecorder) FindOne(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockBookRepo)(nil).FindOne), varargs...)
+}
+
 // Insert mocks base method
-func (m *MockBookRepo) Insert(arg0 context.Context, arg1 *entity.Book) (int64, error) {
+func (m *MockBookRepo) Insert(arg0 context.Context, arg1 *entity.Book, arg2 ...sqkit.InsertOption) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Insert", arg0, arg1)
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Insert", varargs...)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Insert indicates an expected call of Insert
-func (mr *MockBookRepoMockRecorder) Insert(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockBookRepoMockRecorder) Insert(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockBookRepo)(nil).Insert), arg0, arg1)
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockBookRepo)(nil).Insert), varargs...)
 }
 
 // Patch mocks base method
@@ -0,0 +1,136 @@
+package dbtxn
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// Coordinator log the commit decision of two-phase commit. The decision is
+	// recorded before the prepared transactions are committed so the recovery
+	// commit the rest of them when failure in the middle of commit. The table
+	// is created by `database/pg/migration/3_dbtxn_decision.up.sql`.
+	Coordinator struct {
+		DB       *sql.DB
+		Table    string        // by default is `dbtxn_decision`
+		MinAge   time.Duration // age of prepared transaction without decision before it is rolled back, by default is 10m
+		Interval time.Duration // interval of periodic recovery, by default is 1m
+	}
+)
+
+// DefaultDecisionTable is default table name of coordinator log
+const DefaultDecisionTable = "dbtxn_decision"
+
+const (
+	defaultMinAge           = 10 * time.Minute
+	defaultRecoveryInterval = time.Minute
+)
+
+// Run recover the orphaned prepared transactions of db every interval until
+// the context is done. Recover error is logged and retried on next interval.
+func (c *Coordinator) Run(ctx context.Context, db *sql.DB) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = defaultRecoveryInterval
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		resolved, err := c.Recover(ctx, db)
+		if len(resolved) > 0 {
+			logrus.Warnf("dbtxn: resolve orphaned prepared transactions: %s", strings.Join(resolved, ", "))
+		}
+		if err != nil {
+			logrus.Warnf("dbtxn: %s", err.Error())
+		}
+	}
+}
+
+// Recover the orphaned prepared transactions of db with the coordinator
+// resolver and remove the decision of resolved transactions
+func (c *Coordinator) Recover(ctx context.Context, db *sql.DB) ([]string, error) {
+	resolved, err := Recover(ctx, db, c.Resolve)
+	if len(resolved) > 0 {
+		if forgetErr := c.forget(ctx, resolved); err == nil {
+			err = forgetErr
+		}
+	}
+	return resolved, err
+}
+
+// Resolve commit the prepared transaction with recorded decision and rollback
+// the one without decision (presumed abort) when it is older than min age.
+// The younger one without decision is pending as it may be still in-flight.
+func (c *Coordinator) Resolve(ctx context.Context, p PreparedTxn) (Resolution, error) {
+	var one int
+	err := sq.
+		Select("1").
+		From(c.table()).
+		Where(sq.Eq{"gid": p.GID}).
+		PlaceholderFormat(c.placeholder()).
+		RunWith(c.DB).
+		QueryRowContext(ctx).
+		Scan(&one)
+	switch {
+	case err == sql.ErrNoRows && time.Since(p.PreparedAt) < c.minAge():
+		return ResolvePending, nil
+	case err == sql.ErrNoRows:
+		return ResolveRollback, nil
+	case err != nil:
+		return ResolvePending, err
+	}
+	return ResolveCommit, nil
+}
+
+func (c *Coordinator) record(ctx context.Context, gids []string) error {
+	now := time.Now()
+	builder := sq.
+		Insert(c.table()).
+		Columns("gid", "decided_at").
+		PlaceholderFormat(c.placeholder()).
+		RunWith(c.DB)
+	for _, gid := range gids {
+		builder = builder.Values(gid, now)
+	}
+	_, err := builder.ExecContext(ctx)
+	return err
+}
+
+func (c *Coordinator) forget(ctx context.Context, gids []string) error {
+	_, err := sq.
+		Delete(c.table()).
+		Where(sq.Eq{"gid": gids}).
+		PlaceholderFormat(c.placeholder()).
+		RunWith(c.DB).
+		ExecContext(ctx)
+	return err
+}
+
+func (c *Coordinator) table() string {
+	if c.Table == "" {
+		return DefaultDecisionTable
+	}
+	return c.Table
+}
+
+func (c *Coordinator) minAge() time.Duration {
+	if c.MinAge <= 0 {
+		return defaultMinAge
+	}
+	return c.MinAge
+}
+
+func (c *Coordinator) placeholder() sq.PlaceholderFormat {
+	if isMySQL(c.DB) {
+		return sq.Question
+	}
+	return sq.Dollar
+}
@@ -0,0 +1,146 @@
+package dbtxn_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+)
+
+func TestCoordinator_Resolve(t *testing.T) {
+	testcases := []struct {
+		testName    string
+		preparedAt  time.Time
+		mockFn      func(sqlmock.Sqlmock)
+		expected    dbtxn.Resolution
+		expectedErr string
+	}{
+		{
+			testName:   "commit when decision is recorded",
+			preparedAt: time.Now().Add(-time.Hour),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT 1 FROM dbtxn_decision WHERE gid = \$1`).
+					WithArgs("dbtxn_1").
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			},
+			expected: dbtxn.ResolveCommit,
+		},
+		{
+			testName:   "commit young transaction when decision is recorded",
+			preparedAt: time.Now(),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT 1 FROM dbtxn_decision WHERE gid = \$1`).
+					WithArgs("dbtxn_1").
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			},
+			expected: dbtxn.ResolveCommit,
+		},
+		{
+			testName:   "pending when no decision and young",
+			preparedAt: time.Now().Add(-time.Minute),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT 1 FROM dbtxn_decision WHERE gid = \$1`).
+					WithArgs("dbtxn_1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expected: dbtxn.ResolvePending,
+		},
+		{
+			testName:   "rollback when no decision",
+			preparedAt: time.Now().Add(-time.Hour),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT 1 FROM dbtxn_decision WHERE gid = \$1`).
+					WithArgs("dbtxn_1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expected: dbtxn.ResolveRollback,
+		},
+		{
+			testName:   "query error",
+			preparedAt: time.Now().Add(-time.Hour),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT 1 FROM dbtxn_decision`).
+					WillReturnError(errors.New("query-error"))
+			},
+			expectedErr: "query-error",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			tt.mockFn(mock)
+			coordinator := &dbtxn.Coordinator{DB: db}
+			resolution, err := coordinator.Resolve(context.Background(), dbtxn.PreparedTxn{GID: "dbtxn_1", PreparedAt: tt.preparedAt})
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, resolution)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCoordinator_Recover(t *testing.T) {
+	coordinatorDB, coordinatorMock, _ := sqlmock.New()
+	coordinatorMock.ExpectQuery(`SELECT 1 FROM decisions WHERE gid = \$1`).
+		WithArgs("dbtxn_1").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	coordinatorMock.ExpectQuery(`SELECT 1 FROM decisions WHERE gid = \$1`).
+		WithArgs("dbtxn_2").
+		WillReturnError(sql.ErrNoRows)
+	coordinatorMock.ExpectQuery(`SELECT 1 FROM decisions WHERE gid = \$1`).
+		WithArgs("dbtxn_3").
+		WillReturnError(sql.ErrNoRows)
+	coordinatorMock.ExpectExec(`DELETE FROM decisions WHERE gid IN \(\$1,\$2\)`).
+		WithArgs("dbtxn_1", "dbtxn_2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	db, mock, _ := sqlmock.New()
+	mock.ExpectQuery("SELECT gid, prepared FROM pg_prepared_xacts").
+		WillReturnRows(sqlmock.NewRows([]string{"gid", "prepared"}).
+			AddRow("dbtxn_1", time.Now().Add(-2*time.Minute)).
+			AddRow("dbtxn_2", time.Now().Add(-2*time.Minute)).
+			AddRow("dbtxn_3", time.Now()))
+	mock.ExpectExec("COMMIT PREPARED 'dbtxn_1'").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK PREPARED 'dbtxn_2'").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	coordinator := &dbtxn.Coordinator{DB: coordinatorDB, Table: "decisions", MinAge: time.Minute}
+	resolved, err := coordinator.Recover(context.Background(), db)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dbtxn_1", "dbtxn_2"}, resolved)
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.NoError(t, coordinatorMock.ExpectationsWereMet())
+}
+
+func TestCoordinator_Run(t *testing.T) {
+	coordinatorDB, coordinatorMock, _ := sqlmock.New()
+	coordinatorMock.ExpectQuery(`SELECT 1 FROM dbtxn_decision WHERE gid = \$1`).
+		WithArgs("dbtxn_1").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	coordinatorMock.ExpectExec(`DELETE FROM dbtxn_decision WHERE gid IN \(\$1\)`).
+		WithArgs("dbtxn_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	db, mock, _ := sqlmock.New()
+	mock.ExpectQuery("SELECT gid, prepared FROM pg_prepared_xacts").
+		WillReturnRows(sqlmock.NewRows([]string{"gid", "prepared"}).AddRow("dbtxn_1", time.Now()))
+	mock.ExpectExec("COMMIT PREPARED 'dbtxn_1'").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	coordinator := &dbtxn.Coordinator{DB: coordinatorDB, Interval: 10 * time.Millisecond}
+	go func() { done <- coordinator.Run(ctx, db) }()
+
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil && coordinatorMock.ExpectationsWereMet() == nil
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"sync"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/typical-go/typical-go/pkg/errkit"
@@ -14,10 +16,23 @@ const ContextKey key = iota
 
 type (
 	key int
-	// Context of transaction
+	// Context of transaction. Context with Parent is nested transaction
+	// which use savepoint of the parent transaction.
 	Context struct {
-		TxMap map[*sql.DB]Tx
-		Errs  errkit.Errors
+		TxMap      map[*sql.DB]Tx
+		Errs       errkit.Errors
+		Parent     *Context
+		Savepoint  string
+		Options    Options
+		HookErrs   errkit.Errors
+		optErr     error
+		counter    int
+		onCommit   []HookFn
+		onRollback []HookFn
+		tracker    *tracker
+		mtx        sync.Mutex // guard the outermost transaction from auto rollback
+		closed     bool
+		canceled   error
 	}
 	// CommitFn is commit function to close the transaction
 	CommitFn func() error
@@ -39,9 +54,24 @@ func NewContext() *Context {
 	return &Context{TxMap: make(map[*sql.DB]Tx)}
 }
 
-// Begin transaction
-func Begin(parent *context.Context) *Context {
+// Begin transaction. Begin within transaction context is nested transaction
+// where its commit release the savepoint and its error only rollback to the
+// savepoint. Options of nested transaction must not conflict with the
+// outermost transaction. The outermost transaction is rolled back
+// automatically when the parent context is done.
+func Begin(parent *context.Context, opts ...Option) *Context {
 	c := NewContext()
+	c.Options = newOptions(opts)
+	if p := Find(*parent); p != nil {
+		root := p.root()
+		c.Parent = p
+		c.Savepoint = root.nextSavepoint()
+		c.optErr = root.Options.conflict(c.Options)
+		c.AppendError(c.optErr)
+		c.Options = root.Options
+	} else {
+		c.track(*parent)
+	}
 	*parent = context.WithValue(*parent, ContextKey, c)
 	return c
 }
@@ -77,7 +107,7 @@ func Find(ctx context.Context) *Context {
 // Error of transaction
 func Error(ctx context.Context) error {
 	if c := Find(ctx); c != nil {
-		return c.Errs.Unwrap()
+		return unwrapErrs(c.Errs)
 	}
 	return nil
 }
@@ -86,36 +116,110 @@ func Error(ctx context.Context) error {
 // Context
 //
 
-// Begin transaction
+// Begin transaction or create savepoint for nested transaction
 func (c *Context) Begin(ctx context.Context, db *sql.DB) (sq.StdSqlCtx, error) {
+	return c.begin(ctx, db)
+}
+
+func (c *Context) begin(ctx context.Context, db *sql.DB) (Tx, error) {
+	if c.optErr != nil {
+		return nil, c.optErr
+	}
+	if c.Parent == nil {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		if c.canceled != nil {
+			return nil, c.canceled
+		}
+	}
 	tx, ok := c.TxMap[db]
 	if ok {
 		return tx, nil
 	}
 
-	tx, err := db.BeginTx(ctx, nil)
+	if c.Parent != nil {
+		tx, err := c.Parent.begin(ctx, db)
+		if err != nil {
+			c.AppendError(err)
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT "+c.Savepoint); err != nil {
+			c.AppendError(err)
+			return nil, err
+		}
+		c.TxMap[db] = tx
+		return tx, nil
+	}
+
+	stmts, err := c.Options.statements(db)
+	if err != nil {
+		c.AppendError(err)
+		return nil, err
+	}
+	if c.Options.TwoPhase {
+		tx, err = beginTwoPhase(ctx, db, c.Options)
+	} else {
+		tx, err = db.BeginTx(ctx, c.Options.txOptions())
+	}
 	if err != nil {
 		c.AppendError(err)
 		return nil, err
 	}
 	c.TxMap[db] = tx
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			c.AppendError(err)
+			return nil, err
+		}
+	}
 	return tx, nil
 }
 
-// Commit if no error
+// Commit if no error. Nested transaction release the savepoint if no error
+// or rollback to the savepoint otherwise.
 func (c *Context) Commit() error {
+	if c.Parent != nil {
+		return c.commitSavepoint()
+	}
+
+	c.mtx.Lock()
+	c.closed = true
+	canceled := c.canceled
+	c.mtx.Unlock()
+	if canceled != nil {
+		c.runHooks(c.onRollback)
+		return canceled
+	}
+
 	var errs errkit.Errors
-	if len(c.Errs) > 0 {
+	rollback := len(c.Errs) > 0
+	if rollback {
 		for _, tx := range c.TxMap {
 			errs = append(errs, tx.Rollback())
 		}
+	} else if c.Options.TwoPhase {
+		errs = c.commitTwoPhase()
 	} else {
 		for _, tx := range c.TxMap {
 			errs = append(errs, tx.Commit())
 		}
 	}
 
-	return errs.Unwrap()
+	err := unwrapErrs(errs)
+	switch {
+	case rollback:
+		c.untrack(ReasonError)
+	case err != nil:
+		c.untrack(ReasonCommitError)
+	default:
+		c.untrack("")
+	}
+	if rollback || err != nil {
+		c.runHooks(c.onRollback)
+	} else {
+		c.runHooks(c.onCommit)
+	}
+	return err
 }
 
 // AppendError to append error to txn context
@@ -126,3 +230,56 @@ func (c *Context) AppendError(err error) bool {
 	}
 	return false
 }
+
+func (c *Context) commitSavepoint() error {
+	query := "RELEASE SAVEPOINT " + c.Savepoint
+	if len(c.Errs) > 0 {
+		query = "ROLLBACK TO SAVEPOINT " + c.Savepoint
+	}
+
+	errs := errkit.Errors{c.optErr}
+	for _, tx := range c.TxMap {
+		if _, err := tx.ExecContext(context.Background(), query); err != nil {
+			// NOTE: the parent transaction is broken
+			c.Parent.AppendError(err)
+			errs = append(errs, err)
+		}
+	}
+
+	if len(c.Errs) > 0 {
+		c.runHooks(c.onRollback)
+	} else {
+		// NOTE: hooks wait for the outcome of the parent
+		c.Parent.onCommit = append(c.Parent.onCommit, c.onCommit...)
+		c.Parent.onRollback = append(c.Parent.onRollback, c.onRollback...)
+		c.onCommit, c.onRollback = nil, nil
+	}
+	return unwrapErrs(errs)
+}
+
+func (c *Context) root() *Context {
+	for c.Parent != nil {
+		c = c.Parent
+	}
+	return c
+}
+
+func (c *Context) nextSavepoint() string {
+	c.counter++
+	return fmt.Sprintf("dbtxn_sp_%d", c.counter)
+}
+
+// unwrapErrs return the error as is when there is single error to keep its
+// type (e.g. *pq.Error)
+func unwrapErrs(errs errkit.Errors) error {
+	var nonNil errkit.Errors
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	if len(nonNil) == 1 {
+		return nonNil[0]
+	}
+	return nonNil.Unwrap()
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
 )
@@ -154,3 +158,316 @@ func TestAppendError(t *testing.T) {
 		require.EqualError(t, dbtxn.Error(ctx), "some-error-1; some-error-2")
 	})
 }
+
+func TestNestedTransaction(t *testing.T) {
+	t.Run("release savepoint when no error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		ctx := context.Background()
+		outer := dbtxn.Begin(&ctx)
+		innerCtx := ctx
+		inner := dbtxn.Begin(&innerCtx)
+		require.Equal(t, outer, inner.Parent)
+		require.Equal(t, "dbtxn_sp_1", inner.Savepoint)
+
+		_, err := dbtxn.Use(innerCtx, db)
+		require.NoError(t, err)
+		require.NoError(t, inner.Commit())
+		require.NoError(t, outer.Commit())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback to savepoint when error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT dbtxn_sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT dbtxn_sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		ctx := context.Background()
+		outer := dbtxn.Begin(&ctx)
+		_, err := dbtxn.Use(ctx, db)
+		require.NoError(t, err)
+
+		innerCtx := ctx
+		inner := dbtxn.Begin(&innerCtx)
+		handler, err := dbtxn.Use(innerCtx, db)
+		require.NoError(t, err)
+		handler.AppendError(errors.New("some-error"))
+		require.EqualError(t, dbtxn.Error(innerCtx), "some-error")
+		require.NoError(t, inner.Commit())
+		require.NoError(t, dbtxn.Error(ctx))
+
+		innerCtx2 := ctx
+		inner2 := dbtxn.Begin(&innerCtx2)
+		_, err = dbtxn.Use(innerCtx2, db)
+		require.NoError(t, err)
+		require.NoError(t, inner2.Commit())
+
+		require.NoError(t, outer.Commit())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("savepoint error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT dbtxn_sp_1").WillReturnError(errors.New("savepoint-error"))
+		mock.ExpectRollback()
+
+		ctx := context.Background()
+		outer := dbtxn.Begin(&ctx)
+		innerCtx := ctx
+		dbtxn.Begin(&innerCtx)
+
+		_, err := dbtxn.Use(innerCtx, db)
+		require.EqualError(t, err, "savepoint-error")
+		require.NoError(t, dbtxn.Error(ctx))
+		outer.AppendError(err)
+		require.NoError(t, outer.Commit())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("release error break the parent", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT dbtxn_sp_1").WillReturnError(errors.New("release-error"))
+		mock.ExpectRollback()
+
+		ctx := context.Background()
+		outer := dbtxn.Begin(&ctx)
+		innerCtx := ctx
+		inner := dbtxn.Begin(&innerCtx)
+		_, err := dbtxn.Use(innerCtx, db)
+		require.NoError(t, err)
+
+		require.EqualError(t, inner.Commit(), "release-error")
+		require.EqualError(t, dbtxn.Error(ctx), "release-error")
+		require.NoError(t, outer.Commit())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBegin_options(t *testing.T) {
+	t.Run("isolation, read-only and statement timeout", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("SET LOCAL statement_timeout = 1500").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		ctx := context.Background()
+		txn := dbtxn.Begin(&ctx,
+			dbtxn.Isolation(sql.LevelSerializable),
+			dbtxn.ReadOnly(),
+			dbtxn.StatementTimeout(1500*time.Millisecond),
+		)
+		require.Equal(t, dbtxn.Options{
+			Isolation:        sql.LevelSerializable,
+			ReadOnly:         true,
+			StatementTimeout: 1500 * time.Millisecond,
+		}, txn.Options)
+
+		_, err := dbtxn.Use(ctx, db)
+		require.NoError(t, err)
+		require.NoError(t, txn.Commit())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("statement timeout error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("SET LOCAL statement_timeout = 10").WillReturnError(errors.New("set-error"))
+
+		ctx := context.Background()
+		dbtxn.Begin(&ctx, dbtxn.StatementTimeout(10*time.Millisecond))
+		_, err := dbtxn.Use(ctx, db)
+		require.EqualError(t, err, "set-error")
+		require.EqualError(t, dbtxn.Error(ctx), "set-error")
+	})
+
+	testcases := []struct {
+		testName    string
+		outer       []dbtxn.Option
+		nested      []dbtxn.Option
+		expectedErr string
+	}{
+		{
+			testName: "inherit outer options",
+			outer:    []dbtxn.Option{dbtxn.Isolation(sql.LevelSerializable), dbtxn.ReadOnly()},
+		},
+		{
+			testName: "same options",
+			outer:    []dbtxn.Option{dbtxn.Isolation(sql.LevelSerializable)},
+			nested:   []dbtxn.Option{dbtxn.Isolation(sql.LevelSerializable)},
+		},
+		{
+			testName:    "conflict isolation",
+			outer:       []dbtxn.Option{dbtxn.Isolation(sql.LevelReadCommitted)},
+			nested:      []dbtxn.Option{dbtxn.Isolation(sql.LevelSerializable)},
+			expectedErr: "dbtxn: conflict isolation level 'Serializable' with 'Read Committed'",
+		},
+		{
+			testName:    "conflict read-only",
+			nested:      []dbtxn.Option{dbtxn.ReadOnly()},
+			expectedErr: "dbtxn: conflict read-only with read-write transaction",
+		},
+		{
+			testName:    "conflict statement timeout",
+			outer:       []dbtxn.Option{dbtxn.StatementTimeout(time.Second)},
+			nested:      []dbtxn.Option{dbtxn.StatementTimeout(time.Minute)},
+			expectedErr: "dbtxn: conflict statement timeout '1m0s' with '1s'",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			ctx := context.Background()
+			outer := dbtxn.Begin(&ctx, tt.outer...)
+			nested := dbtxn.Begin(&ctx, tt.nested...)
+			require.Equal(t, outer.Options, nested.Options)
+
+			if tt.expectedErr != "" {
+				db, _, _ := sqlmock.New()
+				_, err := dbtxn.Use(ctx, db)
+				require.EqualError(t, err, tt.expectedErr)
+				require.EqualError(t, dbtxn.Error(ctx), tt.expectedErr)
+				require.EqualError(t, nested.Commit(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, dbtxn.Error(ctx))
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	testcases := []struct {
+		testName string
+		err      error
+		expected bool
+	}{
+		{testName: "nil"},
+		{testName: "other error", err: errors.New("some-error")},
+		{testName: "pq serialization failure", err: &pq.Error{Code: "40001"}, expected: true},
+		{testName: "pq deadlock", err: fmt.Errorf("wrapped: %w", &pq.Error{Code: "40P01"}), expected: true},
+		{testName: "pq unique violation", err: &pq.Error{Code: "23505"}},
+		{testName: "mysql deadlock", err: &mysql.MySQLError{Number: 1213}, expected: true},
+		{testName: "mysql lock wait timeout", err: &mysql.MySQLError{Number: 1205}, expected: true},
+		{testName: "mysql duplicate entry", err: &mysql.MySQLError{Number: 1062}},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			require.Equal(t, tt.expected, dbtxn.IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	serializationErr := &pq.Error{Code: "40001", Message: "could not serialize access"}
+
+	t.Run("retry until success", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE books").WillReturnError(serializationErr)
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		attempts := 0
+		retry := &dbtxn.Retry{Backoff: time.Millisecond}
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return updateBook(ctx, db)
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("retry commit error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit().WillReturnError(serializationErr)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := dbtxn.WithRetry(context.Background(), func(ctx context.Context) error {
+			return updateBook(ctx, db)
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("surface final error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		for i := 0; i < 2; i++ {
+			mock.ExpectBegin()
+			mock.ExpectExec("UPDATE books").WillReturnError(serializationErr)
+			mock.ExpectRollback()
+		}
+
+		attempts := 0
+		retry := &dbtxn.Retry{MaxAttempts: 2, Backoff: time.Millisecond}
+		err := retry.Do(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return updateBook(ctx, db)
+		})
+		require.Equal(t, serializationErr, err)
+		require.Equal(t, 2, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not retry other error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE books").WillReturnError(errors.New("some-error"))
+		mock.ExpectRollback()
+
+		attempts := 0
+		err := dbtxn.WithRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return updateBook(ctx, db)
+		})
+		require.EqualError(t, err, "some-error")
+		require.Equal(t, 1, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not retry in outer transaction", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE books").WillReturnError(serializationErr)
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx := context.Background()
+		dbtxn.Begin(&ctx)
+		attempts := 0
+		err := dbtxn.WithRetry(ctx, func(ctx context.Context) error {
+			attempts++
+			return updateBook(ctx, db)
+		})
+		require.Equal(t, serializationErr, err)
+		require.Equal(t, 1, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func updateBook(ctx context.Context, db *sql.DB) error {
+	txn, err := dbtxn.Use(ctx, db)
+	if err != nil {
+		return err
+	}
+	if _, err := txn.ExecContext(ctx, "UPDATE books SET title = 'some-title'"); err != nil {
+		txn.AppendError(err)
+		return err
+	}
+	return nil
+}
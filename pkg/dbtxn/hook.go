@@ -0,0 +1,47 @@
+package dbtxn
+
+import "context"
+
+type (
+	// HookFn is function called after the transaction is committed or
+	// rolled back
+	HookFn func() error
+)
+
+// OnCommit register hook to be called after the transaction committed. The
+// hook is called immediately when the context is not transactional.
+func OnCommit(ctx context.Context, fn HookFn) error {
+	if c := Find(ctx); c != nil {
+		c.OnCommit(fn)
+		return nil
+	}
+	return fn()
+}
+
+// OnRollback register hook to be called after the transaction rolled back.
+// The hook is ignored when the context is not transactional.
+func OnRollback(ctx context.Context, fn HookFn) {
+	if c := Find(ctx); c != nil {
+		c.OnRollback(fn)
+	}
+}
+
+// OnCommit register hook to be called after the transaction committed
+func (c *Context) OnCommit(fn HookFn) {
+	c.onCommit = append(c.onCommit, fn)
+}
+
+// OnRollback register hook to be called after the transaction rolled back
+func (c *Context) OnRollback(fn HookFn) {
+	c.onRollback = append(c.onRollback, fn)
+}
+
+// runHooks in order and collect the errors in HookErrs
+func (c *Context) runHooks(hooks []HookFn) {
+	c.onCommit, c.onRollback = nil, nil
+	for _, fn := range hooks {
+		if err := fn(); err != nil {
+			c.HookErrs = append(c.HookErrs, err)
+		}
+	}
+}
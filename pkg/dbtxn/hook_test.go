@@ -0,0 +1,144 @@
+package dbtxn_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+)
+
+func TestHook(t *testing.T) {
+	testcases := []struct {
+		testName         string
+		mockFn           func(sqlmock.Sqlmock)
+		txnErr           error
+		expectedCalls    []string
+		expectedHookErrs string
+	}{
+		{
+			testName: "commit",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			expectedCalls:    []string{"commit-1", "commit-2"},
+			expectedHookErrs: "hook-error",
+		},
+		{
+			testName: "rollback",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			txnErr:        errors.New("some-error"),
+			expectedCalls: []string{"rollback"},
+		},
+		{
+			testName: "commit error",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit().WillReturnError(errors.New("commit-error"))
+			},
+			expectedCalls: []string{"rollback"},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			tt.mockFn(mock)
+
+			var calls []string
+			ctx := context.Background()
+			txn := dbtxn.Begin(&ctx)
+			handler, err := dbtxn.Use(ctx, db)
+			require.NoError(t, err)
+			handler.AppendError(tt.txnErr)
+
+			require.NoError(t, dbtxn.OnCommit(ctx, func() error {
+				calls = append(calls, "commit-1")
+				return errors.New("hook-error")
+			}))
+			dbtxn.OnRollback(ctx, func() error {
+				calls = append(calls, "rollback")
+				return nil
+			})
+			txn.OnCommit(func() error {
+				calls = append(calls, "commit-2")
+				return nil
+			})
+			require.Empty(t, calls)
+
+			txn.Commit()
+			require.Equal(t, tt.expectedCalls, calls)
+			if tt.expectedHookErrs != "" {
+				require.EqualError(t, txn.HookErrs.Unwrap(), tt.expectedHookErrs)
+			} else {
+				require.Empty(t, txn.HookErrs)
+			}
+			// hook error is not transaction error
+			require.Equal(t, tt.txnErr, dbtxn.Error(ctx))
+		})
+	}
+}
+
+func TestHook_nonTransactional(t *testing.T) {
+	var calls []string
+	ctx := context.Background()
+	require.EqualError(t, dbtxn.OnCommit(ctx, func() error {
+		calls = append(calls, "commit")
+		return errors.New("hook-error")
+	}), "hook-error")
+	dbtxn.OnRollback(ctx, func() error {
+		calls = append(calls, "rollback")
+		return nil
+	})
+	require.Equal(t, []string{"commit"}, calls)
+}
+
+func TestHook_nested(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT dbtxn_sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT dbtxn_sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT dbtxn_sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	var calls []string
+	hook := func(name string) dbtxn.HookFn {
+		return func() error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	ctx := context.Background()
+	outer := dbtxn.Begin(&ctx)
+	outer.OnCommit(hook("outer-commit"))
+
+	failedCtx := ctx
+	failed := dbtxn.Begin(&failedCtx)
+	handler, err := dbtxn.Use(failedCtx, db)
+	require.NoError(t, err)
+	handler.AppendError(errors.New("some-error"))
+	failed.OnCommit(hook("failed-commit"))
+	failed.OnRollback(hook("failed-rollback"))
+	require.NoError(t, failed.Commit())
+	require.Equal(t, []string{"failed-rollback"}, calls)
+
+	succeedCtx := ctx
+	succeed := dbtxn.Begin(&succeedCtx)
+	_, err = dbtxn.Use(succeedCtx, db)
+	require.NoError(t, err)
+	succeed.OnCommit(hook("succeed-commit"))
+	succeed.OnRollback(hook("succeed-rollback"))
+	require.NoError(t, succeed.Commit())
+	require.Equal(t, []string{"failed-rollback"}, calls)
+
+	require.NoError(t, outer.Commit())
+	require.Equal(t, []string{"failed-rollback", "outer-commit", "succeed-commit"}, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,161 @@
+package dbtxn
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/typical-go/typical-rest-server/pkg/logruskit"
+)
+
+type (
+	// RequestScope begin transaction per request and commit when the handler
+	// success with 2xx/3xx response or rollback otherwise. The response is
+	// buffered and written after the transaction is closed, so commit failure
+	// is responded with 500 instead of the handler response.
+	RequestScope struct {
+		Methods []string // by default is POST, PUT, PATCH and DELETE
+		Routes  []string // route path (e.g. `/books/:id`), by default is all routes
+		Options []Option
+	}
+	// bufferWriter hold the response until the transaction is closed
+	bufferWriter struct {
+		http.ResponseWriter
+		status int
+		body   bytes.Buffer
+	}
+)
+
+const (
+	// OutcomeKey is key of transaction outcome in echo context and log fields
+	OutcomeKey = "txn"
+	// Committed outcome
+	Committed = "commit"
+	// RolledBack outcome
+	RolledBack = "rollback"
+)
+
+var defaultMethods = []string{
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// Middleware of echo to begin transaction for the request
+func (s *RequestScope) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !s.match(c) {
+			return next(c)
+		}
+
+		req := c.Request()
+		ctx := req.Context()
+		txn := Begin(&ctx, s.Options...)
+		c.SetRequest(req.WithContext(ctx))
+
+		res := c.Response()
+		writer := res.Writer
+		header := writer.Header().Clone()
+		buf := &bufferWriter{ResponseWriter: writer}
+		res.Writer = buf
+		defer func() {
+			res.Writer = writer
+			if r := recover(); r != nil {
+				txn.AppendError(fmt.Errorf("dbtxn: panic: %v", r))
+				txn.Commit()
+				panic(r)
+			}
+		}()
+
+		err := next(c)
+		if err != nil {
+			txn.AppendError(err)
+		} else if res.Status >= http.StatusBadRequest {
+			txn.AppendError(fmt.Errorf("dbtxn: response status %d", res.Status))
+		}
+
+		outcome := Committed
+		if len(txn.Errs) > 0 {
+			outcome = RolledBack
+		}
+		commitErr := txn.Commit()
+		switch {
+		case commitErr != nil && outcome == Committed:
+			// NOTE: discard the buffered response and headers of handler
+			outcome = RolledBack
+			res.Writer = writer
+			restoreHeader(writer.Header(), header)
+			res.Status = http.StatusOK
+			res.Size = 0
+			res.Committed = false
+			err = echo.NewHTTPError(http.StatusInternalServerError).SetInternal(commitErr)
+		case commitErr != nil:
+			c.Logger().Errorf("dbtxn: %s", commitErr.Error())
+			fallthrough
+		default:
+			if flushErr := buf.flush(writer); flushErr != nil && err == nil {
+				err = flushErr
+			}
+		}
+		c.Set(OutcomeKey, outcome)
+		logruskit.PutField(&ctx, OutcomeKey, outcome)
+		return err
+	}
+}
+
+func (s *RequestScope) match(c echo.Context) bool {
+	methods := s.Methods
+	if len(methods) < 1 {
+		methods = defaultMethods
+	}
+	if !contains(methods, c.Request().Method) {
+		return false
+	}
+	return len(s.Routes) < 1 || contains(s.Routes, c.Path())
+}
+
+func restoreHeader(dst, src http.Header) {
+	for key := range dst {
+		delete(dst, key)
+	}
+	for key, values := range src {
+		dst[key] = values
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// bufferWriter
+//
+
+func (w *bufferWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// Flush is no-op as the response is written after the transaction is closed
+func (w *bufferWriter) Flush() {}
+
+func (w *bufferWriter) flush(writer http.ResponseWriter) error {
+	if w.status != 0 {
+		writer.WriteHeader(w.status)
+	}
+	if w.body.Len() < 1 {
+		return nil
+	}
+	_, err := writer.Write(w.body.Bytes())
+	return err
+}
@@ -0,0 +1,201 @@
+package dbtxn_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"github.com/typical-go/typical-rest-server/pkg/logruskit"
+)
+
+func TestRequestScope(t *testing.T) {
+	testcases := []struct {
+		testName        string
+		scope           *dbtxn.RequestScope
+		method          string
+		handler         func(*sql.DB) echo.HandlerFunc
+		mockFn          func(sqlmock.Sqlmock)
+		expectedErr     string
+		expectedCode    int
+		expectedBody    string
+		expectedHeader  http.Header
+		expectedOutcome interface{}
+	}{
+		{
+			testName: "commit when success",
+			scope:    &dbtxn.RequestScope{},
+			method:   http.MethodPost,
+			handler: func(db *sql.DB) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					require.NoError(t, updateBook(c.Request().Context(), db))
+					return c.String(http.StatusCreated, "some-book")
+				}
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedCode:    http.StatusCreated,
+			expectedBody:    "some-book",
+			expectedOutcome: dbtxn.Committed,
+		},
+		{
+			testName: "500 when commit error",
+			scope:    &dbtxn.RequestScope{},
+			method:   http.MethodPost,
+			handler: func(db *sql.DB) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					require.NoError(t, updateBook(c.Request().Context(), db))
+					c.Response().Header().Set("Location", "/books/1")
+					c.Response().Header().Set("X-Total-Count", "1")
+					return c.String(http.StatusCreated, "some-book")
+				}
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit().WillReturnError(errors.New("commit-error"))
+			},
+			expectedErr:  "code=500, message=Internal Server Error, internal=commit-error",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "{\"message\":\"Internal Server Error\"}\n",
+			expectedHeader: http.Header{
+				"X-Request-Id": {"some-id"},
+				"Content-Type": {"application/json; charset=UTF-8"},
+			},
+			expectedOutcome: dbtxn.RolledBack,
+		},
+		{
+			testName: "rollback when error",
+			scope:    &dbtxn.RequestScope{},
+			method:   http.MethodPut,
+			handler: func(db *sql.DB) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					require.NoError(t, updateBook(c.Request().Context(), db))
+					return errors.New("some-error")
+				}
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			expectedErr:     "some-error",
+			expectedCode:    http.StatusInternalServerError,
+			expectedBody:    "{\"message\":\"Internal Server Error\"}\n",
+			expectedOutcome: dbtxn.RolledBack,
+		},
+		{
+			testName: "rollback when 4xx response",
+			scope:    &dbtxn.RequestScope{},
+			method:   http.MethodDelete,
+			handler: func(db *sql.DB) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					require.NoError(t, updateBook(c.Request().Context(), db))
+					return c.NoContent(http.StatusUnprocessableEntity)
+				}
+			},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			expectedCode:    http.StatusUnprocessableEntity,
+			expectedOutcome: dbtxn.RolledBack,
+		},
+		{
+			testName: "skip other method",
+			scope:    &dbtxn.RequestScope{},
+			method:   http.MethodGet,
+			handler: func(db *sql.DB) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					require.Nil(t, dbtxn.Find(c.Request().Context()))
+					return c.NoContent(http.StatusOK)
+				}
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			testName: "skip other route",
+			scope:    &dbtxn.RequestScope{Routes: []string{"/authors"}},
+			method:   http.MethodPost,
+			handler: func(db *sql.DB) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					require.Nil(t, dbtxn.Find(c.Request().Context()))
+					return c.NoContent(http.StatusOK)
+				}
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			testName: "configured method and route",
+			scope:    &dbtxn.RequestScope{Methods: []string{http.MethodGet}, Routes: []string{"/books"}},
+			method:   http.MethodGet,
+			handler: func(db *sql.DB) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					require.NotNil(t, dbtxn.Find(c.Request().Context()))
+					return c.NoContent(http.StatusOK)
+				}
+			},
+			expectedCode:    http.StatusOK,
+			expectedOutcome: dbtxn.Committed,
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			if tt.mockFn != nil {
+				tt.mockFn(mock)
+			}
+
+			ctx := context.Background()
+			logruskit.PutField(&ctx, "req_id", "some-id")
+			req := httptest.NewRequest(tt.method, "/books", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			rec.Header().Set("X-Request-Id", "some-id")
+			c := echo.New().NewContext(req, rec)
+			c.SetPath("/books")
+
+			err := tt.scope.Middleware(tt.handler(db))(c)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				c.Error(err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expectedCode, rec.Code)
+			require.Equal(t, tt.expectedBody, rec.Body.String())
+			if tt.expectedHeader != nil {
+				require.Equal(t, tt.expectedHeader, rec.Header())
+			}
+			require.Equal(t, tt.expectedOutcome, c.Get(dbtxn.OutcomeKey))
+			require.Equal(t, tt.expectedOutcome, logruskit.GetFields(ctx)[dbtxn.OutcomeKey])
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRequestScope_panic(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPost, "/books", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	handler := (&dbtxn.RequestScope{}).Middleware(func(c echo.Context) error {
+		require.NoError(t, updateBook(c.Request().Context(), db))
+		panic("some-panic")
+	})
+
+	require.PanicsWithValue(t, "some-panic", func() { handler(c) })
+	require.NoError(t, mock.ExpectationsWereMet())
+}
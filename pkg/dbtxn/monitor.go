@@ -0,0 +1,169 @@
+package dbtxn
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// OpenTxn is transaction which not committed or rolled back yet
+	OpenTxn struct {
+		Caller    string    `json:"caller"`
+		StartedAt time.Time `json:"started_at"`
+		Age       string    `json:"age"`
+	}
+	// tracker of the outermost transaction
+	tracker struct {
+		caller    string
+		startedAt time.Time
+		timer     *time.Timer
+		done      chan struct{}
+		once      sync.Once
+	}
+)
+
+// Rollback reasons
+const (
+	ReasonError         = "error"
+	ReasonCommitError   = "commit_error"
+	ReasonContextCancel = "context_canceled"
+)
+
+// LeakThreshold is duration of open transaction before it is warned as
+// leaked (e.g. forgotten commit). Zero disable the warning.
+var LeakThreshold = 30 * time.Second
+
+// Metrics of transactions which exposed in `/debug/vars` as `dbtxn`
+var Metrics = expvar.NewMap("dbtxn")
+
+var (
+	openTxns   = make(map[*Context]*tracker)
+	openTxnMtx sync.Mutex
+	pkgPrefix  = reflect.TypeOf(Context{}).PkgPath() + "."
+)
+
+func init() {
+	Metrics.Set("open_transactions", expvar.Func(func() interface{} {
+		return OpenTransactions()
+	}))
+	Metrics.Set("rollback_reasons", new(expvar.Map).Init())
+}
+
+// OpenTransactions return the open transactions sorted by start time
+func OpenTransactions() []OpenTxn {
+	openTxnMtx.Lock()
+	defer openTxnMtx.Unlock()
+	txns := make([]OpenTxn, 0, len(openTxns))
+	for _, t := range openTxns {
+		txns = append(txns, OpenTxn{
+			Caller:    t.caller,
+			StartedAt: t.startedAt,
+			Age:       time.Since(t.startedAt).String(),
+		})
+	}
+	sort.Slice(txns, func(i, j int) bool {
+		return txns[i].StartedAt.Before(txns[j].StartedAt)
+	})
+	return txns
+}
+
+// track the transaction until it is committed, rolled back or its context
+// is done where the transaction is rolled back automatically
+func (c *Context) track(ctx context.Context) {
+	t := &tracker{
+		caller:    caller(),
+		startedAt: time.Now(),
+		done:      make(chan struct{}),
+	}
+	c.tracker = t
+
+	openTxnMtx.Lock()
+	openTxns[c] = t
+	openTxnMtx.Unlock()
+	Metrics.Add("open", 1)
+	Metrics.Add("begun", 1)
+
+	if LeakThreshold > 0 {
+		threshold := LeakThreshold
+		t.timer = time.AfterFunc(threshold, func() {
+			Metrics.Add("leaked", 1)
+			logrus.Warnf("dbtxn: transaction open for more than %s by %s", threshold, t.caller)
+		})
+	}
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				c.cancel(ctx.Err())
+			case <-t.done:
+			}
+		}()
+	}
+}
+
+// cancel rollback the transaction as its context is done. The hooks are
+// called later by Commit in the goroutine of the transaction owner.
+func (c *Context) cancel(err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.canceled = fmt.Errorf("dbtxn: rollback by %w", err)
+	for _, tx := range c.TxMap {
+		tx.Rollback()
+	}
+	c.untrack(ReasonContextCancel)
+}
+
+// untrack the transaction and record the outcome. Empty reason is commit.
+func (c *Context) untrack(reason string) {
+	t := c.tracker
+	if t == nil {
+		return
+	}
+	t.once.Do(func() {
+		if t.timer != nil {
+			t.timer.Stop()
+		}
+		close(t.done)
+
+		openTxnMtx.Lock()
+		delete(openTxns, c)
+		openTxnMtx.Unlock()
+
+		Metrics.Add("open", -1)
+		Metrics.Add("duration_ms", time.Since(t.startedAt).Milliseconds())
+		if reason == "" {
+			Metrics.Add("committed", 1)
+		} else {
+			Metrics.Add("rolled_back", 1)
+			Metrics.Get("rollback_reasons").(*expvar.Map).Add(reason, 1)
+		}
+	})
+}
+
+// caller of Begin outside this package
+func caller() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPrefix) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
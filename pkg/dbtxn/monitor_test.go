@@ -0,0 +1,107 @@
+package dbtxn_test
+
+import (
+	"context"
+	"expvar"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+)
+
+func TestOpenTransactions(t *testing.T) {
+	committed := metric("committed")
+	rolledBack := metric("rolled_back")
+
+	ctx := context.Background()
+	txn := dbtxn.Begin(&ctx)
+	nested := dbtxn.Begin(&ctx)
+
+	require.Equal(t, 1, openByMonitorTest())
+	require.NoError(t, nested.Commit())
+	require.Equal(t, 1, openByMonitorTest())
+	require.NoError(t, txn.Commit())
+	require.Equal(t, 0, openByMonitorTest())
+	require.Equal(t, committed+1, metric("committed"))
+	require.Equal(t, rolledBack, metric("rolled_back"))
+}
+
+func TestRollbackOnContextCancel(t *testing.T) {
+	canceled := reason(dbtxn.ReasonContextCancel)
+	db, mock, _ := sqlmock.New()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	txn := dbtxn.Begin(&ctx)
+	_, err := dbtxn.Use(ctx, db)
+	require.NoError(t, err)
+
+	var rollback bool
+	dbtxn.OnRollback(ctx, func() error {
+		rollback = true
+		return nil
+	})
+
+	cancel()
+	require.Eventually(t, func() bool {
+		return openByMonitorTest() == 0
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = txn.Begin(ctx, db)
+	require.EqualError(t, err, "dbtxn: rollback by context canceled")
+	require.EqualError(t, txn.Commit(), "dbtxn: rollback by context canceled")
+	require.True(t, rollback)
+	require.Equal(t, canceled+1, reason(dbtxn.ReasonContextCancel))
+}
+
+func TestLeakWarning(t *testing.T) {
+	defer func(threshold time.Duration) { dbtxn.LeakThreshold = threshold }(dbtxn.LeakThreshold)
+	dbtxn.LeakThreshold = 10 * time.Millisecond
+	hook := test.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	leaked := metric("leaked")
+
+	ctx := context.Background()
+	txn := dbtxn.Begin(&ctx)
+	defer txn.Commit()
+
+	require.Eventually(t, func() bool {
+		return hook.LastEntry() != nil
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	require.Contains(t, hook.LastEntry().Message, "dbtxn: transaction open for more than 10ms by ")
+	require.Contains(t, hook.LastEntry().Message, "monitor_test.go:")
+	require.Equal(t, leaked+1, metric("leaked"))
+}
+
+func openByMonitorTest() int {
+	var n int
+	for _, txn := range dbtxn.OpenTransactions() {
+		if strings.Contains(txn.Caller, "monitor_test.go:") {
+			n++
+		}
+	}
+	return n
+}
+
+func metric(key string) int64 {
+	if v, ok := dbtxn.Metrics.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func reason(key string) int64 {
+	reasons := dbtxn.Metrics.Get("rollback_reasons").(*expvar.Map)
+	if v, ok := reasons.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package dbtxn
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// Options of transaction
+	Options struct {
+		Isolation        sql.IsolationLevel
+		ReadOnly         bool
+		StatementTimeout time.Duration // postgres only
+		TwoPhase         bool
+		Coordinator      *Coordinator // log the commit decision of two-phase commit
+	}
+	// Option to begin transaction
+	Option func(*Options)
+)
+
+// Isolation level of transaction
+func Isolation(level sql.IsolationLevel) Option {
+	return func(o *Options) {
+		o.Isolation = level
+	}
+}
+
+// ReadOnly transaction
+func ReadOnly() Option {
+	return func(o *Options) {
+		o.ReadOnly = true
+	}
+}
+
+// StatementTimeout abort any statement of transaction that takes more than
+// the duration. Only supported by postgres with `SET LOCAL statement_timeout`.
+func StatementTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.StatementTimeout = d
+	}
+}
+
+// TwoPhaseCommit prepare the transaction of every database and record the
+// commit decision to the coordinator before commit them to avoid partial
+// commit across multiple databases. Postgres require
+// `max_prepared_transactions` to be configured.
+func TwoPhaseCommit(coordinator *Coordinator) Option {
+	return func(o *Options) {
+		o.TwoPhase = true
+		o.Coordinator = coordinator
+	}
+}
+
+func newOptions(opts []Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// conflict return error when the nested options is not match with the
+// transaction options. Zero option of nested transaction is inherited.
+func (o Options) conflict(nested Options) error {
+	if nested.Isolation != sql.LevelDefault && nested.Isolation != o.Isolation {
+		return fmt.Errorf("dbtxn: conflict isolation level '%s' with '%s'", nested.Isolation, o.Isolation)
+	}
+	if nested.ReadOnly && !o.ReadOnly {
+		return fmt.Errorf("dbtxn: conflict read-only with read-write transaction")
+	}
+	if nested.StatementTimeout != 0 && nested.StatementTimeout != o.StatementTimeout {
+		return fmt.Errorf("dbtxn: conflict statement timeout '%s' with '%s'", nested.StatementTimeout, o.StatementTimeout)
+	}
+	if nested.TwoPhase && !o.TwoPhase {
+		return fmt.Errorf("dbtxn: conflict two-phase commit with one-phase commit transaction")
+	}
+	return nil
+}
+
+func (o Options) txOptions() *sql.TxOptions {
+	if o.Isolation == sql.LevelDefault && !o.ReadOnly {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: o.Isolation, ReadOnly: o.ReadOnly}
+}
+
+func (o Options) statements(db *sql.DB) ([]string, error) {
+	if o.StatementTimeout <= 0 {
+		return nil, nil
+	}
+	if isMySQL(db) {
+		// NOTE: mysql has no transaction scoped variable
+		return nil, fmt.Errorf("dbtxn: statement timeout not support mysql")
+	}
+	return []string{
+		fmt.Sprintf("SET LOCAL statement_timeout = %d", o.StatementTimeout.Milliseconds()),
+	}, nil
+}
+
+func isMySQL(db *sql.DB) bool {
+	return strings.Contains(fmt.Sprintf("%T", db.Driver()), "mysql")
+}
@@ -0,0 +1,99 @@
+package dbtxn
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// Balancer strategy to choose the replica
+	Balancer int
+	// Replicas of primary database
+	Replicas struct {
+		DBs      []*sql.DB
+		Balancer Balancer
+		next     uint32
+	}
+)
+
+const (
+	// RoundRobin choose the replica in turn
+	RoundRobin Balancer = iota
+	// LeastConn choose the replica with least in-use connections
+	LeastConn
+)
+
+const forcePrimaryKey key = iota + 1
+
+var (
+	replicaMap = make(map[*sql.DB]*Replicas)
+	replicaMtx sync.RWMutex
+)
+
+// SetReplicas register the replicas of primary database for `UseRead`.
+// Empty replicas unregister the primary.
+func SetReplicas(primary *sql.DB, balancer Balancer, replicas ...*sql.DB) {
+	replicaMtx.Lock()
+	defer replicaMtx.Unlock()
+	if len(replicas) < 1 {
+		delete(replicaMap, primary)
+		return
+	}
+	replicaMap[primary] = &Replicas{DBs: replicas, Balancer: balancer}
+}
+
+// ForcePrimary read from primary database for read-after-write consistency
+func ForcePrimary(parent *context.Context) {
+	*parent = context.WithValue(*parent, forcePrimaryKey, true)
+}
+
+// IsForcePrimary return true if the context is forced to read from primary
+func IsForcePrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	force, _ := ctx.Value(forcePrimaryKey).(bool)
+	return force
+}
+
+// UseRead is Use for read-only query which routed to the replica of db when
+// not in transaction and not forced to primary
+func UseRead(ctx context.Context, db *sql.DB) (*UseHandler, error) {
+	if ctx == nil {
+		return nil, errors.New("dbtxn: missing context.Context")
+	}
+	if Find(ctx) != nil || IsForcePrimary(ctx) {
+		return Use(ctx, db)
+	}
+	return &UseHandler{StdSqlCtx: Replica(db)}, nil
+}
+
+// Replica of primary database or the primary itself if no replica
+func Replica(primary *sql.DB) *sql.DB {
+	replicaMtx.RLock()
+	r, ok := replicaMap[primary]
+	replicaMtx.RUnlock()
+	if !ok {
+		return primary
+	}
+	return r.Pick()
+}
+
+// Pick replica according the balancer
+func (r *Replicas) Pick() *sql.DB {
+	if r.Balancer == LeastConn {
+		picked := r.DBs[0]
+		inUse := picked.Stats().InUse
+		for _, db := range r.DBs[1:] {
+			if n := db.Stats().InUse; n < inUse {
+				picked, inUse = db, n
+			}
+		}
+		return picked
+	}
+	n := atomic.AddUint32(&r.next, 1)
+	return r.DBs[(n-1)%uint32(len(r.DBs))]
+}
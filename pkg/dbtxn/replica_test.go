@@ -0,0 +1,82 @@
+package dbtxn_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+)
+
+func TestUseRead(t *testing.T) {
+	primary, mock, _ := sqlmock.New()
+	replica1, _, _ := sqlmock.New()
+	replica2, _, _ := sqlmock.New()
+	dbtxn.SetReplicas(primary, dbtxn.RoundRobin, replica1, replica2)
+	defer dbtxn.SetReplicas(primary, dbtxn.RoundRobin)
+
+	t.Run("replica", func(t *testing.T) {
+		var used []interface{}
+		for i := 0; i < 3; i++ {
+			handler, err := dbtxn.UseRead(context.Background(), primary)
+			require.NoError(t, err)
+			require.Nil(t, handler.Context)
+			used = append(used, handler.StdSqlCtx)
+		}
+		require.Equal(t, []interface{}{replica1, replica2, replica1}, used)
+	})
+
+	t.Run("no replica", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		handler, err := dbtxn.UseRead(context.Background(), db)
+		require.NoError(t, err)
+		require.Equal(t, db, handler.StdSqlCtx)
+	})
+
+	t.Run("force primary", func(t *testing.T) {
+		ctx := context.Background()
+		dbtxn.ForcePrimary(&ctx)
+		handler, err := dbtxn.UseRead(ctx, primary)
+		require.NoError(t, err)
+		require.Equal(t, primary, handler.StdSqlCtx)
+	})
+
+	t.Run("in transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		ctx := context.Background()
+		dbtxn.Begin(&ctx)
+		handler, err := dbtxn.UseRead(ctx, primary)
+		require.NoError(t, err)
+		require.NotNil(t, handler.Context)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nil context", func(t *testing.T) {
+		_, err := dbtxn.UseRead(nil, primary)
+		require.EqualError(t, err, "dbtxn: missing context.Context")
+	})
+}
+
+func TestReplicas_Pick(t *testing.T) {
+	db1, _, _ := sqlmock.New()
+	db2, _, _ := sqlmock.New()
+	db3, _, _ := sqlmock.New()
+
+	conn1, err := db1.Conn(context.Background())
+	require.NoError(t, err)
+	defer conn1.Close()
+	conn3, err := db3.Conn(context.Background())
+	require.NoError(t, err)
+	defer conn3.Close()
+
+	leastConn := &dbtxn.Replicas{DBs: []*sql.DB{db1, db2, db3}, Balancer: dbtxn.LeastConn}
+	require.Equal(t, db2, leastConn.Pick())
+
+	roundRobin := &dbtxn.Replicas{DBs: []*sql.DB{db1, db2, db3}}
+	require.Equal(t, db1, roundRobin.Pick())
+	require.Equal(t, db2, roundRobin.Pick())
+	require.Equal(t, db3, roundRobin.Pick())
+	require.Equal(t, db1, roundRobin.Pick())
+}
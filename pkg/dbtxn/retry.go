@@ -0,0 +1,109 @@
+package dbtxn
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+type (
+	// Retry transaction on serialization failure or deadlock
+	Retry struct {
+		MaxAttempts int           // by default is 3
+		Backoff     time.Duration // initial backoff which doubled for each retry, by default is 50ms
+		Options     []Option      // transaction options
+	}
+	// TxFn is function run in transaction
+	TxFn func(context.Context) error
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 50 * time.Millisecond
+)
+
+// WithRetry run the function in transaction and retry with default policy
+// when the error is retryable
+func WithRetry(ctx context.Context, fn TxFn, opts ...Option) error {
+	r := &Retry{Options: opts}
+	return r.Do(ctx, fn)
+}
+
+// Do run the function in transaction, commit and retry the whole
+// transaction when the error is retryable. The function is not retried when
+// the context is already in transaction as the outer transaction must be
+// retried instead.
+func (r *Retry) Do(ctx context.Context, fn TxFn) error {
+	if Find(ctx) != nil {
+		_, err := r.run(ctx, fn)
+		return err
+	}
+
+	maxAttempts := r.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = defaultMaxAttempts
+	}
+	backoff := r.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		retryable, err = r.run(ctx, fn)
+		if err == nil || !retryable || attempt >= maxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (r *Retry) run(ctx context.Context, fn TxFn) (retryable bool, err error) {
+	txn := Begin(&ctx, r.Options...)
+	err = fn(ctx)
+	txn.AppendError(err)
+	commitErr := txn.Commit()
+
+	for _, e := range append(txn.Errs, commitErr) {
+		if IsRetryable(e) {
+			retryable = true
+		}
+	}
+	if err == nil {
+		err = unwrapErrs(txn.Errs)
+	}
+	if err == nil {
+		err = commitErr
+	}
+	return retryable, err
+}
+
+// IsRetryable return true if the error is serialization failure or deadlock
+// of lib/pq or go-sql-driver/mysql
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", "40P01": // serialization_failure, deadlock_detected
+			return true
+		}
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1213, 1205: // ER_LOCK_DEADLOCK, ER_LOCK_WAIT_TIMEOUT
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,311 @@
+package dbtxn
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/sirupsen/logrus"
+	"github.com/typical-go/typical-go/pkg/errkit"
+)
+
+type (
+	// PreparedTxn is prepared transaction of two-phase commit. PreparedAt of
+	// mysql is the begin time as XA RECOVER has no timestamp.
+	PreparedTxn struct {
+		GID        string
+		PreparedAt time.Time
+	}
+	// Resolver decide the resolution of orphaned prepared transaction
+	Resolver func(context.Context, PreparedTxn) (Resolution, error)
+	// Resolution of orphaned prepared transaction
+	Resolution int
+	// twoPhaseTx is transaction on dedicated connection with postgres
+	// `PREPARE TRANSACTION` or mysql XA
+	twoPhaseTx struct {
+		conn  *sql.Conn
+		gid   string
+		mysql bool
+		ended bool // XA END is executed
+		state twoPhaseState
+	}
+	twoPhaseState int
+)
+
+// GIDPrefix is prefix of global transaction identifier of two-phase commit
+const GIDPrefix = "dbtxn_"
+
+const (
+	// ResolvePending leave the prepared transaction as is
+	ResolvePending Resolution = iota
+	// ResolveCommit commit the prepared transaction
+	ResolveCommit
+	// ResolveRollback rollback the prepared transaction
+	ResolveRollback
+)
+
+const (
+	txActive twoPhaseState = iota
+	txPrepared
+	txDone
+)
+
+var _ Tx = (*twoPhaseTx)(nil)
+
+// Recover resolve orphaned prepared transactions which left by failure in
+// the middle of two-phase commit and return the resolved GIDs. Use
+// `Coordinator.Recover` to resolve by the recorded commit decision.
+func Recover(ctx context.Context, db *sql.DB, resolver Resolver) ([]string, error) {
+	if resolver == nil {
+		return nil, errors.New("dbtxn: missing resolver")
+	}
+	txns, err := preparedTxns(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var resolved []string
+	for _, txn := range txns {
+		resolution, err := resolver(ctx, txn)
+		if err != nil {
+			return resolved, err
+		}
+		if resolution == ResolvePending {
+			continue
+		}
+		commit := resolution == ResolveCommit
+		if _, err := db.ExecContext(ctx, finishPrepared(isMySQL(db), txn.GID, commit)); err != nil {
+			return resolved, err
+		}
+		resolved = append(resolved, txn.GID)
+	}
+	return resolved, nil
+}
+
+func preparedTxns(ctx context.Context, db *sql.DB) ([]PreparedTxn, error) {
+	mysql := isMySQL(db)
+	query := fmt.Sprintf("SELECT gid, prepared FROM pg_prepared_xacts WHERE database = current_database() AND gid LIKE '%s%%'", GIDPrefix)
+	if mysql {
+		query = "XA RECOVER"
+	}
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txns []PreparedTxn
+	for rows.Next() {
+		var txn PreparedTxn
+		if mysql {
+			var formatID, gtridLength, bqualLength int
+			if err := rows.Scan(&formatID, &gtridLength, &bqualLength, &txn.GID); err != nil {
+				return nil, err
+			}
+			if !strings.HasPrefix(txn.GID, GIDPrefix) {
+				continue
+			}
+			if id, err := xid.FromString(strings.TrimPrefix(txn.GID, GIDPrefix)); err == nil {
+				txn.PreparedAt = id.Time()
+			}
+		} else if err := rows.Scan(&txn.GID, &txn.PreparedAt); err != nil {
+			return nil, err
+		}
+		txns = append(txns, txn)
+	}
+	return txns, rows.Err()
+}
+
+func finishPrepared(mysql bool, gid string, commit bool) string {
+	switch {
+	case mysql && commit:
+		return fmt.Sprintf("XA COMMIT '%s'", gid)
+	case mysql:
+		return fmt.Sprintf("XA ROLLBACK '%s'", gid)
+	case commit:
+		return fmt.Sprintf("COMMIT PREPARED '%s'", gid)
+	default:
+		return fmt.Sprintf("ROLLBACK PREPARED '%s'", gid)
+	}
+}
+
+func beginTwoPhase(ctx context.Context, db *sql.DB, o Options) (*twoPhaseTx, error) {
+	if o.Coordinator == nil || o.Coordinator.DB == nil {
+		return nil, errors.New("dbtxn: two-phase commit require coordinator")
+	}
+	stmts, err := beginTwoPhaseStatements(isMySQL(db), o)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx := &twoPhaseTx{
+		conn:  conn,
+		gid:   GIDPrefix + xid.New().String(),
+		mysql: isMySQL(db),
+	}
+	if tx.mysql {
+		stmts = append(stmts, fmt.Sprintf("XA START '%s'", tx.gid))
+	}
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+func beginTwoPhaseStatements(mysql bool, o Options) ([]string, error) {
+	var modes []string
+	switch o.Isolation {
+	case sql.LevelDefault:
+	case sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSerializable:
+		modes = append(modes, "ISOLATION LEVEL "+strings.ToUpper(o.Isolation.String()))
+	default:
+		return nil, fmt.Errorf("dbtxn: two-phase commit not support isolation level '%s'", o.Isolation)
+	}
+	if o.ReadOnly {
+		modes = append(modes, "READ ONLY")
+	}
+	if !mysql {
+		return []string{strings.TrimSpace("BEGIN " + strings.Join(modes, " "))}, nil
+	}
+	if len(modes) > 0 {
+		// NOTE: XA START not accept transaction characteristic
+		return []string{"SET TRANSACTION " + strings.Join(modes, ", ")}, nil
+	}
+	return nil, nil
+}
+
+// commitTwoPhase prepare all transactions, record the commit decision to the
+// coordinator then commit them. All transactions are rolled back when any of
+// them failed to prepare or the decision is failed to record.
+func (c *Context) commitTwoPhase() errkit.Errors {
+	ctx := context.Background()
+	gids := make([]string, 0, len(c.TxMap))
+	for _, tx := range c.TxMap {
+		if err := tx.(*twoPhaseTx).prepare(); err != nil {
+			return c.rollbackTwoPhase(err)
+		}
+		gids = append(gids, tx.(*twoPhaseTx).gid)
+	}
+	coordinator := c.Options.Coordinator
+	if err := coordinator.record(ctx, gids); err != nil {
+		return c.rollbackTwoPhase(err)
+	}
+
+	var errs errkit.Errors
+	for _, tx := range c.TxMap {
+		errs = append(errs, tx.(*twoPhaseTx).commitPrepared())
+	}
+	if unwrapErrs(errs) != nil {
+		return errs // NOTE: keep the decision for recovery
+	}
+	if err := coordinator.forget(ctx, gids); err != nil {
+		logrus.Warnf("dbtxn: failed to remove commit decision: %s", err.Error())
+	}
+	return errs
+}
+
+func (c *Context) rollbackTwoPhase(err error) errkit.Errors {
+	errs := errkit.Errors{err}
+	for _, tx := range c.TxMap {
+		errs = append(errs, tx.Rollback())
+	}
+	return errs
+}
+
+//
+// twoPhaseTx
+//
+
+func (t *twoPhaseTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.ExecContext(context.Background(), query, args...)
+}
+
+func (t *twoPhaseTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.QueryContext(context.Background(), query, args...)
+}
+
+func (t *twoPhaseTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.QueryRowContext(context.Background(), query, args...)
+}
+
+func (t *twoPhaseTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.conn.ExecContext(ctx, query, args...)
+}
+
+func (t *twoPhaseTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.conn.QueryContext(ctx, query, args...)
+}
+
+func (t *twoPhaseTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.conn.QueryRowContext(ctx, query, args...)
+}
+
+// Commit prepare and commit the transaction
+func (t *twoPhaseTx) Commit() error {
+	if err := t.prepare(); err != nil {
+		t.Rollback()
+		return err
+	}
+	return t.commitPrepared()
+}
+
+// Rollback the transaction either prepared or not
+func (t *twoPhaseTx) Rollback() error {
+	var stmts []string
+	switch t.state {
+	case txDone:
+		return nil
+	case txPrepared:
+		stmts = []string{finishPrepared(t.mysql, t.gid, false)}
+	default:
+		stmts = []string{"ROLLBACK"}
+		if t.mysql {
+			stmts = []string{fmt.Sprintf("XA ROLLBACK '%s'", t.gid)}
+			if !t.ended {
+				stmts = append([]string{fmt.Sprintf("XA END '%s'", t.gid)}, stmts...)
+			}
+		}
+	}
+	return t.finish(stmts...)
+}
+
+func (t *twoPhaseTx) prepare() error {
+	ctx := context.Background()
+	if t.mysql {
+		if _, err := t.conn.ExecContext(ctx, fmt.Sprintf("XA END '%s'", t.gid)); err != nil {
+			return err
+		}
+		t.ended = true
+		if _, err := t.conn.ExecContext(ctx, fmt.Sprintf("XA PREPARE '%s'", t.gid)); err != nil {
+			return err
+		}
+	} else if _, err := t.conn.ExecContext(ctx, fmt.Sprintf("PREPARE TRANSACTION '%s'", t.gid)); err != nil {
+		return err
+	}
+	t.state = txPrepared
+	return nil
+}
+
+func (t *twoPhaseTx) commitPrepared() error {
+	return t.finish(finishPrepared(t.mysql, t.gid, true))
+}
+
+func (t *twoPhaseTx) finish(stmts ...string) error {
+	defer t.conn.Close()
+	t.state = txDone
+	for _, stmt := range stmts {
+		if _, err := t.conn.ExecContext(context.Background(), stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
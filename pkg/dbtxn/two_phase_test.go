@@ -0,0 +1,209 @@
+package dbtxn_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+)
+
+func TestTwoPhaseCommit(t *testing.T) {
+	t.Run("prepare and commit all databases", func(t *testing.T) {
+		coordinatorDB, coordinatorMock, _ := sqlmock.New()
+		coordinatorMock.ExpectExec(`INSERT INTO dbtxn_decision \(gid,decided_at\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		coordinatorMock.ExpectExec(`DELETE FROM dbtxn_decision WHERE gid IN \(\$1,\$2\)`).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		ctx := context.Background()
+		txn := dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(&dbtxn.Coordinator{DB: coordinatorDB}))
+
+		var mocks []sqlmock.Sqlmock
+		for i := 0; i < 2; i++ {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectExec("BEGIN").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec("PREPARE TRANSACTION 'dbtxn_.+'").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("COMMIT PREPARED 'dbtxn_.+'").WillReturnResult(sqlmock.NewResult(0, 0))
+			require.NoError(t, updateBook(ctx, db))
+			mocks = append(mocks, mock)
+		}
+
+		require.NoError(t, txn.Commit())
+		for _, mock := range append(mocks, coordinatorMock) {
+			require.NoError(t, mock.ExpectationsWereMet())
+		}
+	})
+
+	t.Run("rollback when record decision failed", func(t *testing.T) {
+		coordinatorDB, coordinatorMock, _ := sqlmock.New()
+		coordinatorMock.ExpectExec("INSERT INTO dbtxn_decision").WillReturnError(errors.New("record-error"))
+
+		db, mock, _ := sqlmock.New()
+		mock.ExpectExec("BEGIN").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("PREPARE TRANSACTION 'dbtxn_.+'").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK PREPARED 'dbtxn_.+'").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ctx := context.Background()
+		txn := dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(&dbtxn.Coordinator{DB: coordinatorDB}))
+		require.NoError(t, updateBook(ctx, db))
+		require.EqualError(t, txn.Commit(), "record-error")
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.NoError(t, coordinatorMock.ExpectationsWereMet())
+	})
+
+	t.Run("keep decision when commit prepared failed", func(t *testing.T) {
+		coordinatorDB, coordinatorMock, _ := sqlmock.New()
+		coordinatorMock.ExpectExec("INSERT INTO dbtxn_decision").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		db, mock, _ := sqlmock.New()
+		mock.ExpectExec("BEGIN").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("PREPARE TRANSACTION 'dbtxn_.+'").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("COMMIT PREPARED 'dbtxn_.+'").WillReturnError(errors.New("commit-error"))
+
+		ctx := context.Background()
+		txn := dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(&dbtxn.Coordinator{DB: coordinatorDB}))
+		require.NoError(t, updateBook(ctx, db))
+		require.EqualError(t, txn.Commit(), "commit-error")
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.NoError(t, coordinatorMock.ExpectationsWereMet())
+	})
+
+	t.Run("missing coordinator", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		ctx := context.Background()
+		dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(nil))
+		_, err := dbtxn.Use(ctx, db)
+		require.EqualError(t, err, "dbtxn: two-phase commit require coordinator")
+	})
+
+	t.Run("rollback when prepare failed", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectExec("BEGIN ISOLATION LEVEL SERIALIZABLE READ ONLY").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE books").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("PREPARE TRANSACTION 'dbtxn_.+'").WillReturnError(errors.New("prepare-error"))
+		mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		coordinatorDB, _, _ := sqlmock.New()
+		ctx := context.Background()
+		txn := dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(&dbtxn.Coordinator{DB: coordinatorDB}), dbtxn.Isolation(sql.LevelSerializable), dbtxn.ReadOnly())
+		var rolledBack bool
+		txn.OnRollback(func() error {
+			rolledBack = true
+			return nil
+		})
+		require.NoError(t, updateBook(ctx, db))
+		require.EqualError(t, txn.Commit(), "prepare-error")
+		require.True(t, rolledBack)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback when error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectExec("BEGIN").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE books").WillReturnError(errors.New("some-error"))
+		mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		coordinatorDB, _, _ := sqlmock.New()
+		ctx := context.Background()
+		txn := dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(&dbtxn.Coordinator{DB: coordinatorDB}))
+		require.EqualError(t, updateBook(ctx, db), "some-error")
+		require.NoError(t, txn.Commit())
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unsupported isolation level", func(t *testing.T) {
+		db, _, _ := sqlmock.New()
+		ctx := context.Background()
+		dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(&dbtxn.Coordinator{DB: db}), dbtxn.Isolation(sql.LevelSnapshot))
+		_, err := dbtxn.Use(ctx, db)
+		require.EqualError(t, err, "dbtxn: two-phase commit not support isolation level 'Snapshot'")
+	})
+
+	t.Run("conflict with one-phase commit", func(t *testing.T) {
+		ctx := context.Background()
+		dbtxn.Begin(&ctx)
+		dbtxn.Begin(&ctx, dbtxn.TwoPhaseCommit(&dbtxn.Coordinator{}))
+		require.EqualError(t, dbtxn.Error(ctx), "dbtxn: conflict two-phase commit with one-phase commit transaction")
+	})
+}
+
+func TestRecover(t *testing.T) {
+	resolver := func(ctx context.Context, txn dbtxn.PreparedTxn) (dbtxn.Resolution, error) {
+		switch txn.GID {
+		case "dbtxn_1":
+			return dbtxn.ResolveCommit, nil
+		case "dbtxn_2":
+			return dbtxn.ResolveRollback, nil
+		}
+		return dbtxn.ResolvePending, nil
+	}
+	testcases := []struct {
+		testName         string
+		resolver         dbtxn.Resolver
+		mockFn           func(sqlmock.Sqlmock)
+		expectedResolved []string
+		expectedErr      string
+	}{
+		{
+			testName: "resolver",
+			resolver: resolver,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("COMMIT PREPARED 'dbtxn_1'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK PREPARED 'dbtxn_2'").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedResolved: []string{"dbtxn_1", "dbtxn_2"},
+		},
+		{
+			testName: "resolver error",
+			resolver: func(context.Context, dbtxn.PreparedTxn) (dbtxn.Resolution, error) {
+				return dbtxn.ResolvePending, errors.New("resolver-error")
+			},
+			mockFn:      func(mock sqlmock.Sqlmock) {},
+			expectedErr: "resolver-error",
+		},
+		{
+			testName: "resolve error",
+			resolver: resolver,
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("COMMIT PREPARED 'dbtxn_1'").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("ROLLBACK PREPARED 'dbtxn_2'").WillReturnError(errors.New("resolve-error"))
+			},
+			expectedResolved: []string{"dbtxn_1"},
+			expectedErr:      "resolve-error",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectQuery("SELECT gid, prepared FROM pg_prepared_xacts").
+				WillReturnRows(sqlmock.NewRows([]string{"gid", "prepared"}).
+					AddRow("dbtxn_1", time.Now()).
+					AddRow("dbtxn_2", time.Now()).
+					AddRow("dbtxn_3", time.Now()))
+			tt.mockFn(mock)
+
+			resolved, err := dbtxn.Recover(context.Background(), db, tt.resolver)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expectedResolved, resolved)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRecover_MissingResolver(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	_, err := dbtxn.Recover(context.Background(), db, nil)
+	require.EqualError(t, err, "dbtxn: missing resolver")
+}
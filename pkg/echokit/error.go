@@ -1,11 +1,20 @@
 package echokit
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 )
 
+type (
+	// StatusError is error with its http status code
+	StatusError interface {
+		error
+		HTTPStatus() int
+	}
+)
+
 // NewValidErr create ValidationError
 func NewValidErr(message string) *echo.HTTPError {
 	return echo.NewHTTPError(http.StatusUnprocessableEntity, message)
@@ -16,5 +25,9 @@ func HTTPError(err error) *echo.HTTPError {
 	if httpErr, ok := err.(*echo.HTTPError); ok {
 		return httpErr
 	}
+	var statusErr StatusError
+	if errors.As(err, &statusErr) {
+		return echo.NewHTTPError(statusErr.HTTPStatus(), err.Error())
+	}
 	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 }
@@ -2,6 +2,7 @@ package echokit_test
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -10,6 +11,11 @@ import (
 	"github.com/typical-go/typical-rest-server/pkg/echokit"
 )
 
+type statusErr struct{}
+
+func (statusErr) Error() string   { return "some-conflict" }
+func (statusErr) HTTPStatus() int { return http.StatusConflict }
+
 func TestHTTPError(t *testing.T) {
 	testcases := []struct {
 		TestName string
@@ -24,6 +30,11 @@ func TestHTTPError(t *testing.T) {
 			err:      echo.NewHTTPError(99, "some-message"),
 			expected: echo.NewHTTPError(99, "some-message"),
 		},
+		{
+			TestName: "status error",
+			err:      fmt.Errorf("wrapped: %w", statusErr{}),
+			expected: echo.NewHTTPError(http.StatusConflict, "wrapped: some-conflict"),
+		},
 	}
 	for _, tt := range testcases {
 		t.Run(tt.TestName, func(t *testing.T) {
@@ -0,0 +1,83 @@
+package outboxkit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+)
+
+type (
+	// Outbox store event in the same transaction with the business data so
+	// the event is published if and only if the transaction is committed.
+	// The table is created by `database/pg/migration/2_outbox.up.sql`.
+	Outbox struct {
+		DB    *sql.DB
+		Table string // by default is `outbox`
+	}
+	// Event in outbox
+	Event struct {
+		ID        int64           `json:"id"`
+		Topic     string          `json:"topic"`
+		Payload   json.RawMessage `json:"payload"`
+		Attempts  int             `json:"attempts"`
+		CreatedAt time.Time       `json:"created_at"`
+	}
+)
+
+// DefaultTable is default table name of outbox
+const DefaultTable = "outbox"
+
+// ErrEnqueueWithoutTxn is error when enqueue the event outside transaction
+var ErrEnqueueWithoutTxn = errors.New("outboxkit: enqueue require transaction")
+
+// Enqueue event to the outbox within the transaction of context. Payload is
+// marshalled to JSON except for json.RawMessage.
+func (o *Outbox) Enqueue(ctx context.Context, topic string, payload interface{}) (int64, error) {
+	if dbtxn.Find(ctx) == nil {
+		return -1, ErrEnqueueWithoutTxn
+	}
+	if topic == "" {
+		return -1, errors.New("outboxkit: missing topic")
+	}
+	raw, ok := payload.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(payload); err != nil {
+			return -1, fmt.Errorf("outboxkit: %w", err)
+		}
+	}
+
+	txn, err := dbtxn.Use(ctx, o.DB)
+	if err != nil {
+		return -1, err
+	}
+
+	scanner := sq.
+		Insert(tableName(o.Table)).
+		Columns("topic", "payload", "created_at").
+		Values(topic, string(raw), time.Now()).
+		Suffix("RETURNING \"id\"").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(txn).
+		QueryRowContext(ctx)
+
+	var id int64
+	if err := scanner.Scan(&id); err != nil {
+		txn.AppendError(err)
+		return -1, err
+	}
+	return id, nil
+}
+
+func tableName(table string) string {
+	if table == "" {
+		return DefaultTable
+	}
+	return table
+}
@@ -0,0 +1,103 @@
+package outboxkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"github.com/typical-go/typical-rest-server/pkg/outboxkit"
+)
+
+func TestOutbox_Enqueue(t *testing.T) {
+	testcases := []struct {
+		testName    string
+		noTxn       bool
+		topic       string
+		payload     interface{}
+		mockFn      func(sqlmock.Sqlmock)
+		expected    int64
+		expectedErr string
+	}{
+		{
+			testName: "enqueue",
+			topic:    "book.created",
+			payload:  map[string]interface{}{"id": 1},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO outbox (topic,payload,created_at) VALUES ($1,$2,$3) RETURNING "id"`)).
+					WithArgs("book.created", `{"id":1}`, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+			},
+			expected: 10,
+		},
+		{
+			testName: "raw message",
+			topic:    "book.deleted",
+			payload:  json.RawMessage(`{"id":2}`),
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO outbox`)).
+					WithArgs("book.deleted", `{"id":2}`, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+			},
+			expected: 11,
+		},
+		{
+			testName: "insert error",
+			topic:    "book.created",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO outbox`)).
+					WillReturnError(errors.New("insert-error"))
+			},
+			expected:    -1,
+			expectedErr: "insert-error",
+		},
+		{
+			testName:    "without transaction",
+			noTxn:       true,
+			topic:       "book.created",
+			expected:    -1,
+			expectedErr: "outboxkit: enqueue require transaction",
+		},
+		{
+			testName:    "missing topic",
+			expected:    -1,
+			expectedErr: "outboxkit: missing topic",
+		},
+		{
+			testName:    "marshal error",
+			topic:       "book.created",
+			payload:     make(chan int),
+			expected:    -1,
+			expectedErr: "outboxkit: json: unsupported type: chan int",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			if tt.mockFn != nil {
+				tt.mockFn(mock)
+			}
+			ctx := context.Background()
+			if !tt.noTxn {
+				dbtxn.Begin(&ctx)
+			}
+
+			outbox := &outboxkit.Outbox{DB: db}
+			id, err := outbox.Enqueue(ctx, tt.topic, tt.payload)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expected, id)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package outboxkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/sirupsen/logrus"
+	"github.com/typical-go/typical-rest-server/pkg/dbtxn"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+type (
+	// Relay poll undelivered events from the outbox, publish them to the sink
+	// and mark them delivered. The events are locked with `FOR UPDATE SKIP
+	// LOCKED` so multiple relays can run concurrently.
+	Relay struct {
+		DB          *sql.DB
+		Sink        Sink
+		Table       string        // by default is `outbox`
+		BatchSize   uint64        // by default is 100
+		Interval    time.Duration // poll interval, by default is 1s
+		MaxAttempts int           // stop publishing the event after max attempts, by default is unlimited
+	}
+)
+
+const (
+	defaultBatchSize = 100
+	defaultInterval  = time.Second
+)
+
+// Run poll the outbox until the context is done. Poll error is logged and
+// retried on next interval.
+func (r *Relay) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	for {
+		n, err := r.Poll(ctx)
+		if err != nil {
+			logrus.Warnf("outboxkit: %s", err.Error())
+		}
+		if err == nil && uint64(n) >= r.batchSize() {
+			continue // NOTE: more events may be waiting
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Poll a batch of events in order and return number of delivered events.
+// Publishing stop at the first failed event to keep the order and its
+// attempts is increased.
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	if r.Sink == nil {
+		return 0, errors.New("outboxkit: missing sink")
+	}
+
+	txn := dbtxn.Begin(&ctx)
+	events, err := r.lock(ctx)
+	if err != nil {
+		txn.AppendError(err)
+		txn.Commit()
+		return 0, err
+	}
+
+	var ids []int64
+	var publishErr error
+	for _, e := range events {
+		if publishErr = r.Sink.Publish(ctx, e); publishErr != nil {
+			if err := r.update(ctx, sq.Eq{"id": e.ID}, map[string]interface{}{
+				"attempts":   sq.Expr("attempts + 1"),
+				"last_error": publishErr.Error(),
+			}); err != nil {
+				txn.AppendError(err)
+			}
+			break
+		}
+		ids = append(ids, e.ID)
+	}
+	if len(ids) > 0 {
+		if err := r.update(ctx, sq.Eq{"id": ids}, map[string]interface{}{
+			"delivered_at": time.Now(),
+		}); err != nil {
+			txn.AppendError(err)
+		}
+	}
+
+	if err := txn.Commit(); err != nil {
+		return 0, err
+	}
+	if err := dbtxn.Error(ctx); err != nil {
+		return 0, err
+	}
+	return len(ids), publishErr
+}
+
+func (r *Relay) lock(ctx context.Context) ([]*Event, error) {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Select("id", "topic", "payload", "attempts", "created_at").
+		From(tableName(r.Table)).
+		Where(sq.Eq{"delivered_at": nil}).
+		OrderBy("id").
+		Limit(r.batchSize()).
+		PlaceholderFormat(sq.Dollar)
+	if r.MaxAttempts > 0 {
+		builder = builder.Where(sq.Lt{"attempts": r.MaxAttempts})
+	}
+	lock := &sqkit.Lock{Dialect: sqkit.Postgres, Strength: sqkit.ForUpdate, Wait: sqkit.SkipLocked}
+	builder = lock.CompileSelect(builder)
+
+	rows, err := builder.RunWith(txn).QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		e := new(Event)
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.Topic, &payload, &e.Attempts, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Payload = payload
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *Relay) update(ctx context.Context, where sq.Sqlizer, values map[string]interface{}) error {
+	txn, err := dbtxn.Use(ctx, r.DB)
+	if err != nil {
+		return err
+	}
+	_, err = sq.
+		Update(tableName(r.Table)).
+		SetMap(values).
+		Where(where).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(txn).
+		ExecContext(ctx)
+	return err
+}
+
+func (r *Relay) batchSize() uint64 {
+	if r.BatchSize < 1 {
+		return defaultBatchSize
+	}
+	return r.BatchSize
+}
@@ -0,0 +1,152 @@
+package outboxkit_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/outboxkit"
+)
+
+func TestRelay_Poll(t *testing.T) {
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	selectQuery := regexp.QuoteMeta(`SELECT id, topic, payload, attempts, created_at FROM outbox WHERE delivered_at IS NULL ORDER BY id LIMIT 2 FOR UPDATE SKIP LOCKED`)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "topic", "payload", "attempts", "created_at"}).
+			AddRow(1, "book.created", []byte(`{"id":1}`), 0, createdAt).
+			AddRow(2, "book.deleted", []byte(`{"id":2}`), 1, createdAt)
+	}
+	testcases := []struct {
+		testName          string
+		sinkErr           map[int64]error
+		mockFn            func(sqlmock.Sqlmock)
+		expected          int
+		expectedErr       string
+		expectedPublished []int64
+	}{
+		{
+			testName: "deliver all",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WillReturnRows(rows())
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET delivered_at = $1 WHERE id IN ($2,$3)`)).
+					WithArgs(sqlmock.AnyArg(), 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expected:          2,
+			expectedPublished: []int64{1, 2},
+		},
+		{
+			testName: "stop at failed event",
+			sinkErr:  map[int64]error{2: errors.New("sink-error")},
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WillReturnRows(rows())
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET attempts = attempts + 1, last_error = $1 WHERE id = $2`)).
+					WithArgs("sink-error", 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET delivered_at = $1 WHERE id IN ($2)`)).
+					WithArgs(sqlmock.AnyArg(), 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expected:          1,
+			expectedErr:       "sink-error",
+			expectedPublished: []int64{1},
+		},
+		{
+			testName: "select error",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WillReturnError(errors.New("select-error"))
+				mock.ExpectRollback()
+			},
+			expectedErr: "select-error",
+		},
+		{
+			testName: "update error",
+			mockFn: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(selectQuery).WillReturnRows(rows())
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET delivered_at`)).
+					WillReturnError(errors.New("update-error"))
+				mock.ExpectRollback()
+			},
+			expectedErr:       "update-error",
+			expectedPublished: []int64{1, 2},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			tt.mockFn(mock)
+
+			var published []int64
+			relay := &outboxkit.Relay{
+				DB:        db,
+				BatchSize: 2,
+				Sink: outboxkit.SinkFn(func(ctx context.Context, e *outboxkit.Event) error {
+					if err := tt.sinkErr[e.ID]; err != nil {
+						return err
+					}
+					published = append(published, e.ID)
+					return nil
+				}),
+			}
+			n, err := relay.Poll(context.Background())
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.expected, n)
+			require.Equal(t, tt.expectedPublished, published)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRelay_Poll_MaxAttempts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, topic, payload, attempts, created_at FROM outbox WHERE delivered_at IS NULL AND attempts < $1 ORDER BY id LIMIT 100 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "payload", "attempts", "created_at"}))
+	mock.ExpectCommit()
+
+	relay := &outboxkit.Relay{DB: db, Sink: make(outboxkit.ChanSink), MaxAttempts: 5}
+	n, err := relay.Poll(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRelay_Run(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "topic", "payload", "attempts", "created_at"}).
+			AddRow(1, "book.created", []byte(`{"id":1}`), 0, time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE outbox SET delivered_at`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	sink := make(outboxkit.ChanSink, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- (&outboxkit.Relay{DB: db, Sink: sink, Interval: time.Hour}).Run(ctx)
+	}()
+
+	require.Equal(t, "book.created", (<-sink).Topic)
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,77 @@
+package outboxkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type (
+	// Sink publish the event to the subscriber
+	Sink interface {
+		Publish(context.Context, *Event) error
+	}
+	// SinkFn is function as Sink
+	SinkFn func(context.Context, *Event) error
+	// ChanSink publish the event to in-process channel
+	ChanSink chan *Event
+	// WebhookSink publish the event as JSON by HTTP POST request. The event
+	// is failed when the response status is not 2xx.
+	WebhookSink struct {
+		URL    string
+		Header http.Header
+		Client *http.Client // by default is http.DefaultClient
+	}
+)
+
+var _ Sink = (SinkFn)(nil)
+var _ Sink = (ChanSink)(nil)
+var _ Sink = (*WebhookSink)(nil)
+
+// Publish the event
+func (f SinkFn) Publish(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+// Publish the event to the channel and block until it is received or the
+// context is done
+func (s ChanSink) Publish(ctx context.Context, e *Event) error {
+	select {
+	case s <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Publish the event to the webhook
+func (w *WebhookSink) Publish(ctx context.Context, e *Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("outboxkit: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("outboxkit: %w", err)
+	}
+	for key, values := range w.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("outboxkit: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("outboxkit: webhook response status %d", resp.StatusCode)
+	}
+	return nil
+}
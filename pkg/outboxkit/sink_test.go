@@ -0,0 +1,76 @@
+package outboxkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/outboxkit"
+)
+
+func TestChanSink(t *testing.T) {
+	sink := make(outboxkit.ChanSink, 1)
+	event := &outboxkit.Event{ID: 1, Topic: "book.created"}
+	require.NoError(t, sink.Publish(context.Background(), event))
+	require.Equal(t, event, <-sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.EqualError(t, make(outboxkit.ChanSink).Publish(ctx, event), "context canceled")
+}
+
+func TestWebhookSink(t *testing.T) {
+	event := &outboxkit.Event{
+		ID:        1,
+		Topic:     "book.created",
+		Payload:   json.RawMessage(`{"id":1}`),
+		CreatedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	testcases := []struct {
+		testName    string
+		status      int
+		expectedErr string
+	}{
+		{
+			testName: "success",
+			status:   http.StatusNoContent,
+		},
+		{
+			testName:    "error status",
+			status:      http.StatusInternalServerError,
+			expectedErr: "outboxkit: webhook response status 500",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			var header http.Header
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				b, _ := ioutil.ReadAll(r.Body)
+				body = string(b)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			sink := &outboxkit.WebhookSink{
+				URL:    server.URL,
+				Header: http.Header{"Authorization": {"Bearer token"}},
+			}
+			err := sink.Publish(context.Background(), event)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, "application/json", header.Get("Content-Type"))
+			require.Equal(t, "Bearer token", header.Get("Authorization"))
+			require.Equal(t, `{"id":1,"topic":"book.created","payload":{"id":1},"attempts":0,"created_at":"2021-01-02T03:04:05Z"}`, body)
+		})
+	}
+}
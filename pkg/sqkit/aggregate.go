@@ -0,0 +1,77 @@
+package sqkit
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// GroupBy select and group by columns
+	GroupBy []string
+	// Having conditions as squirel query builder
+	Having []interface{}
+	// Aggregate column (e.g. `count(DISTINCT author) AS authors`)
+	Aggregate struct {
+		Func     AggregateFunc
+		Column   string // by default is `*`
+		Distinct bool
+		Alias    string
+	}
+	// AggregateFunc aggregate function
+	AggregateFunc string
+)
+
+const (
+	// Count aggregate function
+	Count AggregateFunc = "count"
+	// Sum aggregate function
+	Sum AggregateFunc = "sum"
+	// Avg aggregate function
+	Avg AggregateFunc = "avg"
+	// Min aggregate function
+	Min AggregateFunc = "min"
+	// Max aggregate function
+	Max AggregateFunc = "max"
+)
+
+var _ SelectOption = (GroupBy)(nil)
+var _ SelectOption = (Having)(nil)
+var _ SelectOption = (*Aggregate)(nil)
+
+// CompileSelect to compile select query for grouping
+func (g GroupBy) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	if len(g) > 0 {
+		return base.Columns(g...).GroupBy(g...)
+	}
+	return base
+}
+
+// CompileSelect to compile select query for filtering group
+func (h Having) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	for _, cond := range h {
+		base = base.Having(cond)
+	}
+	return base
+}
+
+// CompileSelect to compile select query for aggregate column
+func (a *Aggregate) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	if a.Alias != "" {
+		return base.Column(fmt.Sprintf("%s AS %s", a.Expr(), a.Alias))
+	}
+	return base.Column(a.Expr())
+}
+
+// Expr return aggregate expression without alias to be used in Having
+// (e.g. `sqkit.Having{sq.Gt{agg.Expr(): 10}}`)
+func (a *Aggregate) Expr() string {
+	column := a.Column
+	if column == "" {
+		column = "*"
+	}
+	if a.Distinct {
+		column = "DISTINCT " + column
+	}
+	return fmt.Sprintf("%s(%s)", a.Func, column)
+}
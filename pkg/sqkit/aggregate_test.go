@@ -0,0 +1,59 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestAggregate(t *testing.T) {
+	count := &sqkit.Aggregate{Func: sqkit.Count, Alias: "total"}
+	testcases := []struct {
+		testName      string
+		opts          []sqkit.SelectOption
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			testName:      "count",
+			opts:          []sqkit.SelectOption{count},
+			expectedQuery: "SELECT count(*) AS total FROM books",
+		},
+		{
+			testName: "group by with having",
+			opts: []sqkit.SelectOption{
+				sqkit.Eq{"deleted": false},
+				sqkit.GroupBy{"author"},
+				count,
+				&sqkit.Aggregate{Func: sqkit.Count, Column: "publisher", Distinct: true, Alias: "publishers"},
+				sqkit.Having{sq.Gt{count.Expr(): 2}},
+			},
+			expectedQuery: "SELECT author, count(*) AS total, count(DISTINCT publisher) AS publishers FROM books WHERE deleted = ? GROUP BY author HAVING count(*) > ?",
+			expectedArgs:  []interface{}{false, 2},
+		},
+		{
+			testName: "other function",
+			opts: []sqkit.SelectOption{
+				&sqkit.Aggregate{Func: sqkit.Sum, Column: "price"},
+				&sqkit.Aggregate{Func: sqkit.Avg, Column: "price"},
+				&sqkit.Aggregate{Func: sqkit.Min, Column: "price"},
+				&sqkit.Aggregate{Func: sqkit.Max, Column: "price"},
+			},
+			expectedQuery: "SELECT sum(price), avg(price), min(price), max(price) FROM books",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := sq.Select().From("books")
+			for _, opt := range tt.opts {
+				builder = opt.CompileSelect(builder)
+			}
+			query, args, err := builder.ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package sqkit
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+type (
+	// Columns is whitelist of queryable column mapped by field name
+	Columns map[string]string
+	// ColumnTypes is value parser mapped by column name to validate the
+	// query value (e.g. of filter) before sent to database
+	ColumnTypes map[string]ValueParser
+	// ValueParser parse raw query value to column value
+	ValueParser func(string) (interface{}, error)
+)
+
+// ColumnsOf return Columns from table struct (e.g. dbrepo.BookTable) where
+// each string field value is column name. The column name is also the field name.
+func ColumnsOf(table interface{}) Columns {
+	columns := make(Columns)
+	val := reflect.Indirect(reflect.ValueOf(table))
+	if val.Kind() != reflect.Struct {
+		return columns
+	}
+	for i := 0; i < val.NumField(); i++ {
+		if field := val.Field(i); field.Kind() == reflect.String {
+			columns[field.String()] = field.String()
+		}
+	}
+	return columns
+}
+
+// Qualify columns with the table name (e.g. `books.id`) to avoid ambiguous
+// column name when joined
+func Qualify(table string, columns ...string) []string {
+	qualified := make([]string, len(columns))
+	for i, column := range columns {
+		qualified[i] = table + "." + column
+	}
+	return qualified
+}
+
+// Column return column name of field
+func (c Columns) Column(field string) (string, bool) {
+	column, ok := c[field]
+	return column, ok
+}
+
+//
+// ColumnTypes
+//
+
+// ColumnTypesOf return ColumnTypes from entity struct (e.g. entity.Book)
+// by its `column` tag and field type. String field has no parser.
+func ColumnTypesOf(entity interface{}) ColumnTypes {
+	types := make(ColumnTypes)
+	typ := reflect.TypeOf(entity)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return types
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		column := field.Tag.Get("column")
+		if column == "" {
+			continue
+		}
+		if parse := valueParserOf(field.Type); parse != nil {
+			types[column] = parse
+		}
+	}
+	return types
+}
+
+// Parse value of column or return it as is when no parser
+func (c ColumnTypes) Parse(column, value string) (interface{}, error) {
+	if parse, ok := c[column]; ok {
+		return parse(value)
+	}
+	return value, nil
+}
+
+func valueParserOf(typ reflect.Type) ValueParser {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return ParseTime
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ParseInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ParseUint
+	case reflect.Float32, reflect.Float64:
+		return ParseFloat
+	case reflect.Bool:
+		return ParseBool
+	}
+	return nil
+}
+
+// ParseInt value parser
+func ParseInt(s string) (interface{}, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer '%s'", s)
+	}
+	return v, nil
+}
+
+// ParseUint value parser
+func ParseUint(s string) (interface{}, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid unsigned integer '%s'", s)
+	}
+	return v, nil
+}
+
+// ParseFloat value parser
+func ParseFloat(s string) (interface{}, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number '%s'", s)
+	}
+	return v, nil
+}
+
+// ParseBool value parser
+func ParseBool(s string) (interface{}, error) {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid boolean '%s'", s)
+	}
+	return v, nil
+}
+
+// ParseTime value parser for RFC3339 or date (e.g. `2020-01-01`)
+func ParseTime(s string) (interface{}, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if v, err := time.Parse(layout, s); err == nil {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time '%s'", s)
+}
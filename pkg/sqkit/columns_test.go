@@ -0,0 +1,90 @@
+package sqkit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestColumnsOf(t *testing.T) {
+	testcases := []struct {
+		testName string
+		table    interface{}
+		expected sqkit.Columns
+	}{
+		{
+			testName: "table struct",
+			table: struct {
+				ID    string
+				Title string
+				Count int
+			}{ID: "id", Title: "title", Count: 1},
+			expected: sqkit.Columns{"id": "id", "title": "title"},
+		},
+		{
+			testName: "table pointer",
+			table:    &struct{ ID string }{ID: "id"},
+			expected: sqkit.Columns{"id": "id"},
+		},
+		{
+			testName: "not struct",
+			table:    "id",
+			expected: sqkit.Columns{},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			require.Equal(t, tt.expected, sqkit.ColumnsOf(tt.table))
+		})
+	}
+}
+
+func TestColumnTypesOf(t *testing.T) {
+	types := sqkit.ColumnTypesOf(&struct {
+		ID        int64     `column:"id"`
+		Title     string    `column:"title"`
+		Price     float64   `column:"price"`
+		Stock     uint      `column:"stock"`
+		Published *bool     `column:"published"`
+		CreatedAt time.Time `column:"created_at"`
+		Ignored   int
+	}{})
+	require.Len(t, types, 5)
+
+	testcases := []struct {
+		testName    string
+		column      string
+		value       string
+		expected    interface{}
+		expectedErr string
+	}{
+		{column: "id", value: "1", expected: int64(1)},
+		{column: "id", value: "one", expectedErr: "invalid integer 'one'"},
+		{column: "title", value: "one", expected: "one"},
+		{column: "price", value: "1.5", expected: 1.5},
+		{column: "price", value: "cheap", expectedErr: "invalid number 'cheap'"},
+		{column: "stock", value: "-1", expectedErr: "invalid unsigned integer '-1'"},
+		{column: "published", value: "true", expected: true},
+		{column: "published", value: "yes", expectedErr: "invalid boolean 'yes'"},
+		{column: "created_at", value: "2020-01-02", expected: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{column: "created_at", value: "abc", expectedErr: "invalid time 'abc'"},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.column+"="+tt.value, func(t *testing.T) {
+			v, err := types.Parse(tt.column, tt.value)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestQualify(t *testing.T) {
+	require.Equal(t, []string{"books.id", "books.title"}, sqkit.Qualify("books", "id", "title"))
+	require.Equal(t, []string{}, sqkit.Qualify("books"))
+}
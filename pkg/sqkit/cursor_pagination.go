@@ -0,0 +1,181 @@
+package sqkit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// CursorPagination param for keyset pagination. The cursor is the encoded
+	// sort values of the last row of previous page (or the first row when
+	// Backward). All sort columns must be NOT NULL as null never match the
+	// seek predicate, and last sort should be unique (e.g. primary key) to
+	// keep the order stable.
+	CursorPagination struct {
+		Cursor   string
+		Sorts    Sorts
+		Limit    uint64
+		Backward bool
+	}
+	errSqlizer struct {
+		err error
+	}
+)
+
+var _ SelectOption = (*CursorPagination)(nil)
+
+// CompileSelect to compile select query for cursor pagination. Rows of
+// backward page are in reverse order and must be reversed by the caller.
+func (p *CursorPagination) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	sorts := p.Sorts
+	if p.Backward {
+		sorts = sorts.reverse()
+	}
+	if p.Cursor != "" {
+		values, err := DecodeCursor(p.Cursor)
+		if err != nil {
+			return base.Where(errSqlizer{err: err})
+		}
+		if len(values) != len(sorts) {
+			return base.Where(errSqlizer{err: errors.New("sqkit: cursor not match with sorts")})
+		}
+		for i, v := range values {
+			if v == nil {
+				return base.Where(errSqlizer{err: fmt.Errorf("sqkit: cursor contain null value of '%s'", sorts[i].Column)})
+			}
+		}
+		base = base.Where(seekPredicate(sorts, values))
+	}
+	base = sorts.CompileSelect(base)
+	if p.Limit > 0 {
+		base = base.Limit(p.Limit)
+	}
+	return base
+}
+
+// NextCursor return cursor of next page from the last row of current page
+func (p *CursorPagination) NextCursor(last interface{}) (string, error) {
+	return CursorOf(p.Sorts, last)
+}
+
+// PrevCursor return cursor of previous page from the first row of current page
+func (p *CursorPagination) PrevCursor(first interface{}) (string, error) {
+	return CursorOf(p.Sorts, first)
+}
+
+// CursorOf return cursor contain sort values of row. Row is either
+// map[string]interface{} keyed by column or struct with `column` tag.
+func CursorOf(sorts Sorts, row interface{}) (string, error) {
+	values := make([]interface{}, len(sorts))
+	for i, sort := range sorts {
+		v, err := columnValue(row, sort.Column)
+		if err != nil {
+			return "", err
+		}
+		values[i] = v
+	}
+	return EncodeCursor(values...)
+}
+
+// EncodeCursor encode values to opaque cursor
+func EncodeCursor(values ...interface{}) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("sqkit: encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decode values from opaque cursor
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("sqkit: invalid cursor")
+	}
+	var values []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, errors.New("sqkit: invalid cursor")
+	}
+	return values, nil
+}
+
+// seekPredicate return `(a, b) > (?, ?)` when all sorts in same direction,
+// otherwise expanded form `a > ? OR (a = ? AND b < ?)`
+func seekPredicate(sorts Sorts, values []interface{}) sq.Sqlizer {
+	columns := make([]string, len(sorts))
+	descs := make([]bool, len(sorts))
+	sameDirection := true
+	for i, sort := range sorts {
+		columns[i], descs[i] = sort.Dialect.Quote(sort.Column), sort.Desc
+		if descs[i] != descs[0] {
+			sameDirection = false
+		}
+	}
+
+	if sameDirection {
+		op := ">"
+		if descs[0] {
+			op = "<"
+		}
+		if len(columns) == 1 {
+			return sq.Expr(fmt.Sprintf("%s %s ?", columns[0], op), values[0])
+		}
+		return sq.Expr(fmt.Sprintf("(%s) %s (%s)",
+			strings.Join(columns, ", "), op, sq.Placeholders(len(values))), values...)
+	}
+
+	var or sq.Or
+	for i := range columns {
+		var and sq.And
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Eq{columns[j]: values[j]})
+		}
+		if descs[i] {
+			and = append(and, sq.Lt{columns[i]: values[i]})
+		} else {
+			and = append(and, sq.Gt{columns[i]: values[i]})
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+func columnValue(row interface{}, column string) (interface{}, error) {
+	if m, ok := row.(map[string]interface{}); ok {
+		v, ok := m[column]
+		if !ok {
+			return nil, fmt.Errorf("sqkit: missing cursor column '%s'", column)
+		}
+		return v, nil
+	}
+
+	val := reflect.Indirect(reflect.ValueOf(row))
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqkit: cursor row must be struct or map: %T", row)
+	}
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Tag.Get("column") == column {
+			return val.Field(i).Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("sqkit: missing cursor column '%s'", column)
+}
+
+//
+// errSqlizer
+//
+
+// ToSql return the error to fail the query before it is executed
+func (e errSqlizer) ToSql() (string, []interface{}, error) {
+	return "", nil, e.err
+}
@@ -0,0 +1,164 @@
+package sqkit_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestCursorPagination(t *testing.T) {
+	cursor := func(values ...interface{}) string {
+		s, _ := sqkit.EncodeCursor(values...)
+		return s
+	}
+	testcases := []struct {
+		testName string
+		*sqkit.CursorPagination
+		builder       sq.SelectBuilder
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   string
+	}{
+		{
+			testName:         "first page",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "id"}}, Limit: 10},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedQuery:    "SELECT id, title FROM books ORDER BY id ASC LIMIT 10",
+		},
+		{
+			testName:         "single column",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "id", Desc: true}}, Cursor: cursor(7), Limit: 10},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedQuery:    "SELECT id, title FROM books WHERE id < ? ORDER BY id DESC LIMIT 10",
+			expectedArgs:     []interface{}{json.Number("7")},
+		},
+		{
+			testName:         "same direction",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "title"}, {Column: "id"}}, Cursor: cursor("some-title", 7)},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedQuery:    "SELECT id, title FROM books WHERE (title, id) > (?,?) ORDER BY title ASC, id ASC",
+			expectedArgs:     []interface{}{"some-title", json.Number("7")},
+		},
+		{
+			testName:         "mixed direction",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "title", Desc: true}, {Column: "id"}}, Cursor: cursor("some-title", 7)},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedQuery:    "SELECT id, title FROM books WHERE ((title < ?) OR (title = ? AND id > ?)) ORDER BY title DESC, id ASC",
+			expectedArgs:     []interface{}{"some-title", "some-title", json.Number("7")},
+		},
+		{
+			testName:         "backward",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "title", Desc: true}, {Column: "id"}}, Cursor: cursor("some-title", 7), Backward: true},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedQuery:    "SELECT id, title FROM books WHERE ((title > ?) OR (title = ? AND id < ?)) ORDER BY title ASC, id DESC",
+			expectedArgs:     []interface{}{"some-title", "some-title", json.Number("7")},
+		},
+		{
+			testName:         "backward with nulls ordering",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "title", Nulls: sqkit.NullsLast, Dialect: sqkit.Postgres}, {Column: "id", Dialect: sqkit.Postgres}}, Cursor: cursor("some-title", 7), Backward: true},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedQuery:    `SELECT id, title FROM books WHERE ("title", "id") < (?,?) ORDER BY "title" DESC NULLS FIRST, "id" DESC`,
+			expectedArgs:     []interface{}{"some-title", json.Number("7")},
+		},
+		{
+			testName:         "backward with mysql nulls ordering",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "title", Nulls: sqkit.NullsFirst, Dialect: sqkit.MySQL}}, Cursor: cursor("some-title"), Backward: true},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedQuery:    "SELECT id, title FROM books WHERE `title` < ? ORDER BY `title` IS NULL ASC, `title` DESC",
+			expectedArgs:     []interface{}{"some-title"},
+		},
+		{
+			testName:         "dollar placeholder",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "title", Dialect: sqkit.Postgres}, {Column: "id", Dialect: sqkit.Postgres}}, Cursor: cursor("some-title", 7)},
+			builder:          sq.Select("id", "title").From("books").PlaceholderFormat(sq.Dollar),
+			expectedQuery:    `SELECT id, title FROM books WHERE ("title", "id") > ($1,$2) ORDER BY "title" ASC, "id" ASC`,
+			expectedArgs:     []interface{}{"some-title", json.Number("7")},
+		},
+		{
+			testName:         "invalid cursor",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "id"}}, Cursor: "!nvalid"},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedErr:      "sqkit: invalid cursor",
+		},
+		{
+			testName:         "cursor not match with sorts",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "id"}}, Cursor: cursor("some-title", 7)},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedErr:      "sqkit: cursor not match with sorts",
+		},
+		{
+			testName:         "cursor contain null value",
+			CursorPagination: &sqkit.CursorPagination{Sorts: sqkit.Sorts{{Column: "title"}, {Column: "id"}}, Cursor: cursor(nil, 7)},
+			builder:          sq.Select("id", "title").From("books"),
+			expectedErr:      "sqkit: cursor contain null value of 'title'",
+		},
+	}
+
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			query, args, err := tt.CompileSelect(tt.builder).ToSql()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestCursorOf(t *testing.T) {
+	type book struct {
+		ID    int64  `column:"id"`
+		Title string `column:"title"`
+	}
+	testcases := []struct {
+		testName       string
+		sorts          sqkit.Sorts
+		row            interface{}
+		expectedValues []interface{}
+		expectedErr    string
+	}{
+		{
+			testName:       "struct",
+			sorts:          sqkit.Sorts{{Column: "title", Desc: true}, {Column: "id"}},
+			row:            &book{ID: 7, Title: "some-title"},
+			expectedValues: []interface{}{"some-title", json.Number("7")},
+		},
+		{
+			testName:       "map",
+			sorts:          sqkit.Sorts{{Column: "id"}},
+			row:            map[string]interface{}{"id": 7},
+			expectedValues: []interface{}{json.Number("7")},
+		},
+		{
+			testName:    "missing column",
+			sorts:       sqkit.Sorts{{Column: "author"}},
+			row:         &book{},
+			expectedErr: "sqkit: missing cursor column 'author'",
+		},
+		{
+			testName:    "invalid row",
+			sorts:       sqkit.Sorts{{Column: "id"}},
+			row:         7,
+			expectedErr: "sqkit: cursor row must be struct or map: int",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			cursor, err := sqkit.CursorOf(tt.sorts, tt.row)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			values, err := sqkit.DecodeCursor(cursor)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedValues, values)
+		})
+	}
+}
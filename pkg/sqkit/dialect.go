@@ -0,0 +1,36 @@
+package sqkit
+
+import (
+	"strings"
+)
+
+type (
+	// Dialect of database as in `@dbrepo` annotation
+	Dialect string
+)
+
+const (
+	// Postgres dialect
+	Postgres Dialect = "postgres"
+	// MySQL dialect
+	MySQL Dialect = "mysql"
+)
+
+// Quote identifier (e.g. `books.title`) by dialect. Identifier is not quoted
+// when dialect is empty.
+func (d Dialect) Quote(ident string) string {
+	var quote string
+	switch d {
+	case Postgres:
+		quote = `"`
+	case MySQL:
+		quote = "`"
+	default:
+		return ident
+	}
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,29 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestDialect_Quote(t *testing.T) {
+	testcases := []struct {
+		testName string
+		dialect  sqkit.Dialect
+		ident    string
+		expected string
+	}{
+		{dialect: "", ident: "title", expected: "title"},
+		{dialect: sqkit.Postgres, ident: "title", expected: `"title"`},
+		{dialect: sqkit.Postgres, ident: "books.title", expected: `"books"."title"`},
+		{dialect: sqkit.Postgres, ident: `ti"tle`, expected: `"ti""tle"`},
+		{dialect: sqkit.MySQL, ident: "books.title", expected: "`books`.`title`"},
+		{dialect: sqkit.MySQL, ident: "ti`tle", expected: "`ti``tle`"},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.dialect.Quote(tt.ident))
+		})
+	}
+}
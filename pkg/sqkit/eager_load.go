@@ -0,0 +1,82 @@
+package sqkit
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// EagerLoad one-to-many children of parents with `WHERE fk IN (...)`
+	// query instead of query per parent (N+1 query)
+	EagerLoad struct {
+		Table             string
+		ForeignKey        string
+		Columns           []string
+		Options           []SelectOption
+		PlaceholderFormat sq.PlaceholderFormat
+		BatchSize         int // maximum keys per query, by default all keys in single query
+	}
+	// ScanFn scan child row
+	ScanFn func(*sql.Rows) error
+)
+
+// Load children of parent keys. The scan function is called for each child
+// row and responsible to attach the child to its parent.
+func (e *EagerLoad) Load(ctx context.Context, runner sq.StdSqlCtx, keys []interface{}, scan ScanFn) error {
+	keys = uniqueKeys(keys)
+	batchSize := e.BatchSize
+	if batchSize < 1 {
+		batchSize = len(keys)
+	}
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := e.load(ctx, runner, keys[start:end], scan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *EagerLoad) load(ctx context.Context, runner sq.StdSqlCtx, keys []interface{}, scan ScanFn) error {
+	builder := sq.
+		Select(e.Columns...).
+		From(e.Table).
+		Where(sq.Eq{e.ForeignKey: keys}).
+		RunWith(runner)
+	if e.PlaceholderFormat != nil {
+		builder = builder.PlaceholderFormat(e.PlaceholderFormat)
+	}
+	for _, opt := range e.Options {
+		builder = opt.CompileSelect(builder)
+	}
+
+	rows, err := builder.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func uniqueKeys(keys []interface{}) []interface{} {
+	seen := make(map[interface{}]bool)
+	var unique []interface{}
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,83 @@
+package sqkit_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestEagerLoad(t *testing.T) {
+	type review struct {
+		BookID  int64
+		Comment string
+	}
+
+	t.Run("load in batch", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, comment FROM reviews WHERE book_id IN ($1,$2) ORDER BY id ASC`)).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "comment"}).
+				AddRow(1, "comment-1").
+				AddRow(1, "comment-2").
+				AddRow(2, "comment-3"))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, comment FROM reviews WHERE book_id IN ($1) ORDER BY id ASC`)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "comment"}))
+
+		eagerLoad := &sqkit.EagerLoad{
+			Table:             "reviews",
+			ForeignKey:        "book_id",
+			Columns:           []string{"book_id", "comment"},
+			Options:           []sqkit.SelectOption{sqkit.Sorts{{Column: "id"}}},
+			PlaceholderFormat: sq.Dollar,
+			BatchSize:         2,
+		}
+		reviews := make(map[int64][]*review)
+		err := eagerLoad.Load(context.Background(), db, []interface{}{1, 2, 1, 3}, func(rows *sql.Rows) error {
+			r := new(review)
+			if err := rows.Scan(&r.BookID, &r.Comment); err != nil {
+				return err
+			}
+			reviews[r.BookID] = append(reviews[r.BookID], r)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[int64][]*review{
+			1: {{BookID: 1, Comment: "comment-1"}, {BookID: 1, Comment: "comment-2"}},
+			2: {{BookID: 2, Comment: "comment-3"}},
+		}, reviews)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		eagerLoad := &sqkit.EagerLoad{Table: "reviews", ForeignKey: "book_id", Columns: []string{"comment"}}
+		require.NoError(t, eagerLoad.Load(context.Background(), db, nil, nil))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectQuery("SELECT").WillReturnError(errors.New("query-error"))
+		eagerLoad := &sqkit.EagerLoad{Table: "reviews", ForeignKey: "book_id", Columns: []string{"comment"}}
+		err := eagerLoad.Load(context.Background(), db, []interface{}{1}, nil)
+		require.EqualError(t, err, "query-error")
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"comment"}).AddRow("comment-1"))
+		eagerLoad := &sqkit.EagerLoad{Table: "reviews", ForeignKey: "book_id", Columns: []string{"comment"}}
+		err := eagerLoad.Load(context.Background(), db, []interface{}{1}, func(*sql.Rows) error {
+			return errors.New("scan-error")
+		})
+		require.EqualError(t, err, "scan-error")
+	})
+}
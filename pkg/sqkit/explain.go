@@ -0,0 +1,187 @@
+package sqkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// Explain the select query plan with `EXPLAIN (FORMAT JSON)` for postgres
+	// and `EXPLAIN FORMAT=JSON` for mysql
+	Explain struct {
+		Dialect Dialect
+		Analyze bool // execute the query to get actual time, postgres only
+	}
+	// Plan summary of query plan
+	Plan struct {
+		Query    string          `json:"query"`
+		Args     []interface{}   `json:"args"`
+		SeqScans []string        `json:"seq_scans"` // tables with sequential/full table scan
+		Rows     float64         `json:"rows"`      // estimated rows
+		Cost     float64         `json:"cost"`      // estimated total cost
+		Raw      json.RawMessage `json:"raw"`
+	}
+	// SlowQuery explain the select query which is slower than threshold and
+	// pass its plan to the callback
+	SlowQuery struct {
+		Explain   *Explain
+		Threshold time.Duration
+		OnSlow    func(ctx context.Context, plan *Plan, elapsed time.Duration)
+	}
+)
+
+// Compile select query with the options
+func Compile(base sq.SelectBuilder, opts ...SelectOption) (string, []interface{}, error) {
+	for _, opt := range opts {
+		base = opt.CompileSelect(base)
+	}
+	return base.ToSql()
+}
+
+// Run explain of compiled select query and return its plan summary
+func (e *Explain) Run(ctx context.Context, runner sq.StdSqlCtx, base sq.SelectBuilder, opts ...SelectOption) (*Plan, error) {
+	query, args, err := Compile(base, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefix string
+	switch e.Dialect {
+	case Postgres:
+		prefix = "EXPLAIN (FORMAT JSON)"
+		if e.Analyze {
+			prefix = "EXPLAIN (ANALYZE, FORMAT JSON)"
+		}
+	case MySQL:
+		prefix = "EXPLAIN FORMAT=JSON"
+	default:
+		return nil, fmt.Errorf("sqkit: explain not support dialect '%s'", e.Dialect)
+	}
+
+	var raw []byte
+	if err := runner.QueryRowContext(ctx, prefix+" "+query, args...).Scan(&raw); err != nil {
+		return nil, err
+	}
+	plan := &Plan{Query: query, Args: args, Raw: raw}
+	if e.Dialect == Postgres {
+		err = plan.parsePostgres(raw)
+	} else {
+		err = plan.parseMySQL(raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Observe elapsed time of the select query which is compiled from the same
+// builder and options. The query is explained only when elapsed time reach
+// the threshold, so call it after the rows of the query are closed.
+func (s *SlowQuery) Observe(ctx context.Context, runner sq.StdSqlCtx, elapsed time.Duration, base sq.SelectBuilder, opts ...SelectOption) error {
+	if elapsed < s.Threshold || s.OnSlow == nil {
+		return nil
+	}
+	plan, err := s.Explain.Run(ctx, runner, base, opts...)
+	if err != nil {
+		return err
+	}
+	s.OnSlow(ctx, plan, elapsed)
+	return nil
+}
+
+func (p *Plan) parsePostgres(raw []byte) error {
+	type node struct {
+		NodeType     string  `json:"Node Type"`
+		RelationName string  `json:"Relation Name"`
+		TotalCost    float64 `json:"Total Cost"`
+		PlanRows     float64 `json:"Plan Rows"`
+		Plans        []*node `json:"Plans"`
+	}
+	var plans []struct {
+		Plan *node `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return fmt.Errorf("sqkit: invalid query plan: %w", err)
+	}
+	if len(plans) < 1 || plans[0].Plan == nil {
+		return fmt.Errorf("sqkit: invalid query plan: %s", raw)
+	}
+
+	var walk func(*node)
+	walk = func(n *node) {
+		if n.NodeType == "Seq Scan" {
+			p.SeqScans = append(p.SeqScans, n.RelationName)
+		}
+		for _, child := range n.Plans {
+			walk(child)
+		}
+	}
+	root := plans[0].Plan
+	walk(root)
+	p.Rows = root.PlanRows
+	p.Cost = root.TotalCost
+	return nil
+}
+
+func (p *Plan) parseMySQL(raw []byte) error {
+	var plan struct {
+		QueryBlock map[string]interface{} `json:"query_block"`
+	}
+	if err := json.Unmarshal(raw, &plan); err != nil {
+		return fmt.Errorf("sqkit: invalid query plan: %w", err)
+	}
+	if plan.QueryBlock == nil {
+		return fmt.Errorf("sqkit: invalid query plan: %s", raw)
+	}
+
+	var walk func(interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if table, ok := v["table"].(map[string]interface{}); ok {
+				if table["access_type"] == "ALL" {
+					p.SeqScans = append(p.SeqScans, fmt.Sprint(table["table_name"]))
+				}
+				// rows of the last joined table is the estimated result rows
+				p.Rows = mysqlNumber(table["rows_produced_per_join"])
+			}
+			var keys []string
+			for key := range v {
+				if key != "table" {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(v[key])
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(plan.QueryBlock)
+	if costInfo, ok := plan.QueryBlock["cost_info"].(map[string]interface{}); ok {
+		p.Cost = mysqlNumber(costInfo["query_cost"])
+	}
+	return nil
+}
+
+// mysqlNumber of explain json which is either number or string
+func mysqlNumber(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,164 @@
+package sqkit_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestCompile(t *testing.T) {
+	query, args, err := sqkit.Compile(
+		sq.Select("id", "title").From("books").PlaceholderFormat(sq.Dollar),
+		sqkit.Eq{"author": "some-author"},
+		&sqkit.OffsetPagination{Offset: 10, Limit: 5},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id, title FROM books WHERE author = $1 LIMIT 5 OFFSET 10", query)
+	require.Equal(t, []interface{}{"some-author"}, args)
+}
+
+func TestExplain(t *testing.T) {
+	base := sq.Select("id").From("books")
+	testcases := []struct {
+		testName string
+		*sqkit.Explain
+		expectedQuery string
+		rawPlan       string
+		expectedPlan  *sqkit.Plan
+		expectedErr   string
+	}{
+		{
+			testName:      "postgres",
+			Explain:       &sqkit.Explain{Dialect: sqkit.Postgres, Analyze: true},
+			expectedQuery: "EXPLAIN (ANALYZE, FORMAT JSON) SELECT id FROM books WHERE author = ?",
+			rawPlan: `[{"Plan": {"Node Type": "Hash Join", "Total Cost": 35.5, "Plan Rows": 12, "Plans": [
+				{"Node Type": "Seq Scan", "Relation Name": "books", "Total Cost": 20.1, "Plan Rows": 120},
+				{"Node Type": "Hash", "Plans": [{"Node Type": "Index Scan", "Relation Name": "authors"}]}
+			]}, "Execution Time": 0.5}]`,
+			expectedPlan: &sqkit.Plan{
+				Query:    "SELECT id FROM books WHERE author = ?",
+				Args:     []interface{}{"some-author"},
+				SeqScans: []string{"books"},
+				Rows:     12,
+				Cost:     35.5,
+			},
+		},
+		{
+			testName:      "mysql",
+			Explain:       &sqkit.Explain{Dialect: sqkit.MySQL},
+			expectedQuery: "EXPLAIN FORMAT=JSON SELECT id FROM books WHERE author = ?",
+			rawPlan: `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "12.75"}, "nested_loop": [
+				{"table": {"table_name": "books", "access_type": "ALL", "rows_produced_per_join": 100}},
+				{"table": {"table_name": "authors", "access_type": "eq_ref", "rows_produced_per_join": 10}}
+			]}}`,
+			expectedPlan: &sqkit.Plan{
+				Query:    "SELECT id FROM books WHERE author = ?",
+				Args:     []interface{}{"some-author"},
+				SeqScans: []string{"books"},
+				Rows:     10,
+				Cost:     12.75,
+			},
+		},
+		{
+			testName:      "invalid plan",
+			Explain:       &sqkit.Explain{Dialect: sqkit.Postgres},
+			expectedQuery: "EXPLAIN (FORMAT JSON) SELECT id FROM books WHERE author = ?",
+			rawPlan:       `[]`,
+			expectedErr:   "sqkit: invalid query plan: []",
+		},
+		{
+			testName:    "unknown dialect",
+			Explain:     &sqkit.Explain{},
+			expectedErr: "sqkit: explain not support dialect ''",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			if tt.expectedQuery != "" {
+				mock.ExpectQuery(regexp.QuoteMeta(tt.expectedQuery)).
+					WithArgs("some-author").
+					WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(tt.rawPlan))
+			}
+			plan, err := tt.Run(context.Background(), db, base, sqkit.Eq{"author": "some-author"})
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			plan.Raw = nil
+			require.Equal(t, tt.expectedPlan, plan)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSlowQuery(t *testing.T) {
+	base := sq.Select("id").From("books")
+	testcases := []struct {
+		testName      string
+		elapsed       time.Duration
+		rawPlan       string
+		expectedPlans []*sqkit.Plan
+		expectedErr   string
+	}{
+		{
+			testName: "below threshold",
+			elapsed:  99 * time.Millisecond,
+		},
+		{
+			testName: "slow query",
+			elapsed:  100 * time.Millisecond,
+			rawPlan:  `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "books", "Total Cost": 20.1, "Plan Rows": 120}}]`,
+			expectedPlans: []*sqkit.Plan{
+				{
+					Query:    "SELECT id FROM books WHERE author = ?",
+					Args:     []interface{}{"some-author"},
+					SeqScans: []string{"books"},
+					Rows:     120,
+					Cost:     20.1,
+				},
+			},
+		},
+		{
+			testName:    "explain error",
+			elapsed:     time.Second,
+			rawPlan:     `[]`,
+			expectedErr: "sqkit: invalid query plan: []",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			if tt.rawPlan != "" {
+				mock.ExpectQuery(regexp.QuoteMeta("EXPLAIN (FORMAT JSON) SELECT id FROM books WHERE author = ?")).
+					WithArgs("some-author").
+					WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow(tt.rawPlan))
+			}
+			var plans []*sqkit.Plan
+			slowQuery := &sqkit.SlowQuery{
+				Explain:   &sqkit.Explain{Dialect: sqkit.Postgres},
+				Threshold: 100 * time.Millisecond,
+				OnSlow: func(ctx context.Context, plan *sqkit.Plan, elapsed time.Duration) {
+					require.Equal(t, tt.elapsed, elapsed)
+					plan.Raw = nil
+					plans = append(plans, plan)
+				},
+			}
+			err := slowQuery.Observe(context.Background(), db, tt.elapsed, base, sqkit.Eq{"author": "some-author"})
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedPlans, plans)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
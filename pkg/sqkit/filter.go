@@ -0,0 +1,158 @@
+package sqkit
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/typical-go/typical-rest-server/pkg/echokit"
+)
+
+type (
+	// Filter conditions parsed from query parameter
+	Filter []sq.Sqlizer
+	// FilterOperator compile column and query value to condition. The value
+	// is parsed by the column type.
+	FilterOperator func(column, value string, types ColumnTypes) (sq.Sqlizer, error)
+)
+
+var _ SelectOption = (Filter)(nil)
+var _ UpdateOption = (Filter)(nil)
+var _ DeleteOption = (Filter)(nil)
+
+var filterKey = regexp.MustCompile(`^(\w+)\[(\w+)\]$`)
+
+// FilterOperators supported by ParseFilter
+var FilterOperators = map[string]FilterOperator{
+	"eq":      compareOperator(func(c string, v interface{}) sq.Sqlizer { return sq.Eq{c: v} }),
+	"ne":      compareOperator(func(c string, v interface{}) sq.Sqlizer { return sq.NotEq{c: v} }),
+	"gt":      compareOperator(func(c string, v interface{}) sq.Sqlizer { return sq.Gt{c: v} }),
+	"gte":     compareOperator(func(c string, v interface{}) sq.Sqlizer { return sq.GtOrEq{c: v} }),
+	"lt":      compareOperator(func(c string, v interface{}) sq.Sqlizer { return sq.Lt{c: v} }),
+	"lte":     compareOperator(func(c string, v interface{}) sq.Sqlizer { return sq.LtOrEq{c: v} }),
+	"like":    func(c, v string, _ ColumnTypes) (sq.Sqlizer, error) { return sq.Like{c: v}, nil },
+	"ilike":   func(c, v string, _ ColumnTypes) (sq.Sqlizer, error) { return sq.ILike{c: v}, nil },
+	"in":      listOperator(func(c string, v []interface{}) sq.Sqlizer { return sq.Eq{c: v} }),
+	"nin":     listOperator(func(c string, v []interface{}) sq.Sqlizer { return sq.NotEq{c: v} }),
+	"is_null": isNullOperator,
+	"between": betweenOperator,
+}
+
+// ParseFilter parse query parameter (e.g. `title[like]=go%&author[in]=a,b`)
+// to Filter. Plain parameter (e.g. `title=go`) is equal operator and
+// ignored when not in columns. Return validation error for unknown column,
+// unknown operator or bad value of column types (e.g.
+// `sqkit.ColumnTypesOf(entity.Book{})`).
+func ParseFilter(values url.Values, columns Columns, types ColumnTypes) (Filter, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filter Filter
+	for _, key := range keys {
+		field, op := key, "eq"
+		if match := filterKey.FindStringSubmatch(key); match != nil {
+			field, op = match[1], match[2]
+			if _, ok := columns.Column(field); !ok {
+				return nil, echokit.NewValidErr(fmt.Sprintf("unknown filter column '%s'", field))
+			}
+		}
+		column, ok := columns.Column(field)
+		if !ok {
+			continue
+		}
+		operator, ok := FilterOperators[op]
+		if !ok {
+			return nil, echokit.NewValidErr(fmt.Sprintf("unknown filter operator '%s'", op))
+		}
+		for _, value := range values[key] {
+			cond, err := operator(column, value, types)
+			if err != nil {
+				return nil, echokit.NewValidErr(fmt.Sprintf("%s[%s]: %s", field, op, err.Error()))
+			}
+			filter = append(filter, cond)
+		}
+	}
+	return filter, nil
+}
+
+// CompileSelect to compile select query for filtering
+func (f Filter) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	for _, cond := range f {
+		base = base.Where(cond)
+	}
+	return base
+}
+
+// CompileUpdate to compile update query for filtering
+func (f Filter) CompileUpdate(base sq.UpdateBuilder) sq.UpdateBuilder {
+	for _, cond := range f {
+		base = base.Where(cond)
+	}
+	return base
+}
+
+// CompileDelete to compile delete query for filtering
+func (f Filter) CompileDelete(base sq.DeleteBuilder) sq.DeleteBuilder {
+	for _, cond := range f {
+		base = base.Where(cond)
+	}
+	return base
+}
+
+func compareOperator(fn func(string, interface{}) sq.Sqlizer) FilterOperator {
+	return func(column, value string, types ColumnTypes) (sq.Sqlizer, error) {
+		v, err := types.Parse(column, value)
+		if err != nil {
+			return nil, err
+		}
+		return fn(column, v), nil
+	}
+}
+
+func listOperator(fn func(string, []interface{}) sq.Sqlizer) FilterOperator {
+	return func(column, value string, types ColumnTypes) (sq.Sqlizer, error) {
+		var list []interface{}
+		for _, s := range strings.Split(value, ",") {
+			v, err := types.Parse(column, s)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return fn(column, list), nil
+	}
+}
+
+func isNullOperator(column, value string, _ ColumnTypes) (sq.Sqlizer, error) {
+	isNull, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid boolean '%s'", value)
+	}
+	if isNull {
+		return sq.Eq{column: nil}, nil
+	}
+	return sq.NotEq{column: nil}, nil
+}
+
+func betweenOperator(column, value string, types ColumnTypes) (sq.Sqlizer, error) {
+	bounds := strings.Split(value, ",")
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("expect 2 values but got %d", len(bounds))
+	}
+	from, err := types.Parse(column, bounds[0])
+	if err != nil {
+		return nil, err
+	}
+	to, err := types.Parse(column, bounds[1])
+	if err != nil {
+		return nil, err
+	}
+	return sq.Expr(column+" BETWEEN ? AND ?", from, to), nil
+}
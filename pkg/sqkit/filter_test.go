@@ -0,0 +1,135 @@
+package sqkit_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestParseFilter(t *testing.T) {
+	columns := sqkit.ColumnsOf(struct {
+		Title     string
+		Author    string
+		CreatedAt string
+	}{
+		Title:     "title",
+		Author:    "author",
+		CreatedAt: "created_at",
+	})
+	types := sqkit.ColumnTypes{"created_at": sqkit.ParseTime}
+	testcases := []struct {
+		testName      string
+		rawQuery      string
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   string
+	}{
+		{
+			testName:      "no filter",
+			rawQuery:      "limit=10&sort=title",
+			expectedQuery: "SELECT * FROM books",
+		},
+		{
+			testName:      "plain equal",
+			rawQuery:      "title=some-title&limit=10",
+			expectedQuery: "SELECT * FROM books WHERE title = ?",
+			expectedArgs:  []interface{}{"some-title"},
+		},
+		{
+			testName:      "comparison",
+			rawQuery:      "title[like]=go%25&created_at[gte]=2020-01-01&created_at[lt]=2021-01-01",
+			expectedQuery: "SELECT * FROM books WHERE created_at >= ? AND created_at < ? AND title LIKE ?",
+			expectedArgs:  []interface{}{date(2020, 1, 1), date(2021, 1, 1), "go%"},
+		},
+		{
+			testName:      "list",
+			rawQuery:      "author[in]=a,b&title[nin]=c,d",
+			expectedQuery: "SELECT * FROM books WHERE author IN (?,?) AND title NOT IN (?,?)",
+			expectedArgs:  []interface{}{"a", "b", "c", "d"},
+		},
+		{
+			testName:      "is null and between",
+			rawQuery:      "author[is_null]=true&title[is_null]=false&created_at[between]=2020-01-01,2021-01-01",
+			expectedQuery: "SELECT * FROM books WHERE author IS NULL AND created_at BETWEEN ? AND ? AND title IS NOT NULL",
+			expectedArgs:  []interface{}{date(2020, 1, 1), date(2021, 1, 1)},
+		},
+		{
+			testName:      "other operator",
+			rawQuery:      "title[eq]=a&title[ne]=b&author[ilike]=c&created_at[gt]=2020-01-01T10:00:00Z&created_at[lte]=2020-01-02",
+			expectedQuery: "SELECT * FROM books WHERE author ILIKE ? AND created_at > ? AND created_at <= ? AND title = ? AND title <> ?",
+			expectedArgs:  []interface{}{"c", time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), date(2020, 1, 2), "a", "b"},
+		},
+		{
+			testName:    "unknown column",
+			rawQuery:    "password[eq]=secret",
+			expectedErr: "code=422, message=unknown filter column 'password'",
+		},
+		{
+			testName:    "unknown operator",
+			rawQuery:    "title[regex]=.*",
+			expectedErr: "code=422, message=unknown filter operator 'regex'",
+		},
+		{
+			testName:    "bad is_null value",
+			rawQuery:    "title[is_null]=maybe",
+			expectedErr: "code=422, message=title[is_null]: invalid boolean 'maybe'",
+		},
+		{
+			testName:    "bad between value",
+			rawQuery:    "created_at[between]=2020-01-01",
+			expectedErr: "code=422, message=created_at[between]: expect 2 values but got 1",
+		},
+		{
+			testName:    "bad typed value",
+			rawQuery:    "created_at[gte]=abc",
+			expectedErr: "code=422, message=created_at[gte]: invalid time 'abc'",
+		},
+		{
+			testName:    "bad typed list value",
+			rawQuery:    "created_at[in]=2020-01-01,abc",
+			expectedErr: "code=422, message=created_at[in]: invalid time 'abc'",
+		},
+		{
+			testName:    "bad typed between value",
+			rawQuery:    "created_at[between]=2020-01-01,abc",
+			expectedErr: "code=422, message=created_at[between]: invalid time 'abc'",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.rawQuery)
+			filter, err := sqkit.ParseFilter(values, columns, types)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			query, args, err := filter.CompileSelect(sq.Select("*").From("books")).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFilter_CompileUpdate(t *testing.T) {
+	filter := sqkit.Filter{sq.Eq{"name": "dummy-name"}}
+	query, args, _ := filter.CompileUpdate(sq.Update("some-table").Set("column", "column-value")).ToSql()
+	require.Equal(t, "UPDATE some-table SET column = ? WHERE name = ?", query)
+	require.Equal(t, []interface{}{"column-value", "dummy-name"}, args)
+}
+
+func TestFilter_CompileDelete(t *testing.T) {
+	filter := sqkit.Filter{sq.Eq{"name": "dummy-name"}}
+	query, args, _ := filter.CompileDelete(sq.Delete("some-table")).ToSql()
+	require.Equal(t, "DELETE FROM some-table WHERE name = ?", query)
+	require.Equal(t, []interface{}{"dummy-name"}, args)
+}
@@ -0,0 +1,73 @@
+package sqkit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// FullTextSearch search query on columns. Postgres compile to
+	// `to_tsvector(...) @@ plainto_tsquery(...)` and MySQL compile to
+	// `MATCH (...) AGAINST (...)` which require FULLTEXT index on the columns.
+	FullTextSearch struct {
+		Dialect  Dialect
+		Columns  []string
+		Query    string
+		Language string // postgres text search config, by default is `simple`
+		Rank     bool   // order by relevance
+	}
+)
+
+var _ SelectOption = (*FullTextSearch)(nil)
+
+var textSearchConfig = regexp.MustCompile(`^[a-z_]+$`)
+
+// CompileSelect to compile select query for full text search
+func (f *FullTextSearch) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	if f.Query == "" {
+		return base
+	}
+	if len(f.Columns) < 1 {
+		return base.Where(errSqlizer{err: errors.New("sqkit: missing full text search columns")})
+	}
+
+	var match string
+	switch f.Dialect {
+	case Postgres:
+		language := f.Language
+		if language == "" {
+			language = "simple"
+		}
+		if !textSearchConfig.MatchString(language) {
+			return base.Where(errSqlizer{err: fmt.Errorf("sqkit: invalid full text search language '%s'", language)})
+		}
+		var documents []string
+		for _, column := range f.Columns {
+			documents = append(documents, fmt.Sprintf("coalesce(%s, '')", f.Dialect.Quote(column)))
+		}
+		vector := fmt.Sprintf("to_tsvector('%s', %s)", language, strings.Join(documents, " || ' ' || "))
+		query := fmt.Sprintf("plainto_tsquery('%s', ?)", language)
+		match = fmt.Sprintf("%s @@ %s", vector, query)
+		base = base.Where(match, f.Query)
+		if f.Rank {
+			base = base.OrderByClause(fmt.Sprintf("ts_rank(%s, %s) DESC", vector, query), f.Query)
+		}
+	case MySQL:
+		var columns []string
+		for _, column := range f.Columns {
+			columns = append(columns, f.Dialect.Quote(column))
+		}
+		match = fmt.Sprintf("MATCH (%s) AGAINST (? IN NATURAL LANGUAGE MODE)", strings.Join(columns, ", "))
+		base = base.Where(match, f.Query)
+		if f.Rank {
+			base = base.OrderByClause(match+" DESC", f.Query)
+		}
+	default:
+		return base.Where(errSqlizer{err: fmt.Errorf("sqkit: full text search not support dialect '%s'", f.Dialect)})
+	}
+	return base
+}
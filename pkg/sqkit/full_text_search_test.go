@@ -0,0 +1,95 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestFullTextSearch(t *testing.T) {
+	testcases := []struct {
+		testName string
+		*sqkit.FullTextSearch
+		builder       sq.SelectBuilder
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   string
+	}{
+		{
+			testName:       "empty query",
+			FullTextSearch: &sqkit.FullTextSearch{Dialect: sqkit.Postgres, Columns: []string{"title"}},
+			builder:        sq.Select("id").From("books"),
+			expectedQuery:  "SELECT id FROM books",
+		},
+		{
+			testName: "postgres",
+			FullTextSearch: &sqkit.FullTextSearch{
+				Dialect: sqkit.Postgres,
+				Columns: []string{"title", "author"},
+				Query:   "golang",
+			},
+			builder:       sq.Select("id").From("books").PlaceholderFormat(sq.Dollar),
+			expectedQuery: `SELECT id FROM books WHERE to_tsvector('simple', coalesce("title", '') || ' ' || coalesce("author", '')) @@ plainto_tsquery('simple', $1)`,
+			expectedArgs:  []interface{}{"golang"},
+		},
+		{
+			testName: "postgres with rank",
+			FullTextSearch: &sqkit.FullTextSearch{
+				Dialect:  sqkit.Postgres,
+				Columns:  []string{"title"},
+				Query:    "golang",
+				Language: "english",
+				Rank:     true,
+			},
+			builder: sq.Select("id").From("books").PlaceholderFormat(sq.Dollar),
+			expectedQuery: `SELECT id FROM books WHERE to_tsvector('english', coalesce("title", '')) @@ plainto_tsquery('english', $1) ` +
+				`ORDER BY ts_rank(to_tsvector('english', coalesce("title", '')), plainto_tsquery('english', $2)) DESC`,
+			expectedArgs: []interface{}{"golang", "golang"},
+		},
+		{
+			testName: "mysql with rank",
+			FullTextSearch: &sqkit.FullTextSearch{
+				Dialect: sqkit.MySQL,
+				Columns: []string{"title", "author"},
+				Query:   "golang",
+				Rank:    true,
+			},
+			builder: sq.Select("id").From("books"),
+			expectedQuery: "SELECT id FROM books WHERE MATCH (`title`, `author`) AGAINST (? IN NATURAL LANGUAGE MODE) " +
+				"ORDER BY MATCH (`title`, `author`) AGAINST (? IN NATURAL LANGUAGE MODE) DESC",
+			expectedArgs: []interface{}{"golang", "golang"},
+		},
+		{
+			testName:       "invalid language",
+			FullTextSearch: &sqkit.FullTextSearch{Dialect: sqkit.Postgres, Columns: []string{"title"}, Query: "golang", Language: "english'"},
+			builder:        sq.Select("id").From("books"),
+			expectedErr:    "sqkit: invalid full text search language 'english''",
+		},
+		{
+			testName:       "missing columns",
+			FullTextSearch: &sqkit.FullTextSearch{Dialect: sqkit.Postgres, Query: "golang"},
+			builder:        sq.Select("id").From("books"),
+			expectedErr:    "sqkit: missing full text search columns",
+		},
+		{
+			testName:       "unknown dialect",
+			FullTextSearch: &sqkit.FullTextSearch{Columns: []string{"title"}, Query: "golang"},
+			builder:        sq.Select("id").From("books"),
+			expectedErr:    "sqkit: full text search not support dialect ''",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			query, args, err := tt.CompileSelect(tt.builder).ToSql()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package sqkit
+
+import (
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// InsertOption to compile insert query
+	InsertOption interface {
+		CompileInsert(sq.InsertBuilder) sq.InsertBuilder
+	}
+	// CompileInsertFn function
+	CompileInsertFn  func(sq.InsertBuilder) sq.InsertBuilder
+	insertOptionImpl struct {
+		fn CompileInsertFn
+	}
+)
+
+// NewInsertOption return new instance of InsertOption
+func NewInsertOption(fn CompileInsertFn) InsertOption {
+	return &insertOptionImpl{fn: fn}
+}
+
+func (i *insertOptionImpl) CompileInsert(b sq.InsertBuilder) sq.InsertBuilder {
+	return i.fn(b)
+}
@@ -0,0 +1,17 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestNewInsertOption(t *testing.T) {
+	expected := sq.Insert("")
+	insertOpt := sqkit.NewInsertOption(func(sq.InsertBuilder) sq.InsertBuilder {
+		return expected
+	})
+	require.Equal(t, expected, insertOpt.CompileInsert(sq.Insert("")))
+}
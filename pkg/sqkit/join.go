@@ -0,0 +1,117 @@
+package sqkit
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// Join table for select query. Generated repository Find select the
+	// joined columns aliased as `<alias>_<column>` (e.g. `p.name AS p_name`)
+	// to avoid ambiguous column name and fill Rows with the joined columns of
+	// each returned entity, so use new Join for each query. Count and Exists
+	// only join the table (e.g. to filter by the joined columns).
+	// Filter or sort by column which exist in both tables must be qualified
+	// with the table name (e.g. `sqkit.Eq{"books.id": id}`).
+	Join struct {
+		Left    bool // LEFT JOIN instead of INNER JOIN
+		Table   string
+		Alias   string // by default is the table name
+		On      string
+		Columns []string
+		Rows    []map[string]interface{} // joined columns keyed by alias
+	}
+	// ExtraSelector select the columns which not belong to the entity (e.g.
+	// joined columns) in generated repository Find
+	ExtraSelector interface {
+		SelectExtra() []string
+	}
+	// ExtraScanner receive the selected columns which not belong to the
+	// entity (e.g. joined columns) of each row scanned by generated repository
+	ExtraScanner interface {
+		ScanExtra(values map[string]interface{})
+	}
+)
+
+var _ SelectOption = (*Join)(nil)
+var _ ExtraSelector = (*Join)(nil)
+var _ ExtraScanner = (*Join)(nil)
+
+// SelectExtra return the extra columns of ExtraSelector in the options
+func SelectExtra(opts []SelectOption) []string {
+	var columns []string
+	for _, opt := range opts {
+		if selector, ok := opt.(ExtraSelector); ok {
+			columns = append(columns, selector.SelectExtra()...)
+		}
+	}
+	return columns
+}
+
+// ScanExtra pass the extra columns of the row (pointer of scanned value
+// keyed by column name) to ExtraScanner in the options
+func ScanExtra(opts []SelectOption, extra map[string]interface{}) {
+	values := make(map[string]interface{}, len(extra))
+	for column, dest := range extra {
+		v := dest
+		if p, ok := dest.(*interface{}); ok {
+			v = *p
+		}
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		values[column] = v
+	}
+	for _, opt := range opts {
+		if scanner, ok := opt.(ExtraScanner); ok {
+			scanner.ScanExtra(values)
+		}
+	}
+}
+
+// CompileSelect to compile select query with join clause. The joined columns
+// are selected by SelectExtra.
+func (j *Join) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	clause := j.Table
+	if j.Alias != "" {
+		clause = fmt.Sprintf("%s %s", j.Table, j.Alias)
+	}
+	clause = fmt.Sprintf("%s ON %s", clause, j.On)
+
+	if j.Left {
+		return base.LeftJoin(clause)
+	}
+	return base.Join(clause)
+}
+
+// SelectExtra return the joined columns aliased as `<alias>_<column>`
+func (j *Join) SelectExtra() []string {
+	columns := make([]string, len(j.Columns))
+	for i, column := range j.Columns {
+		columns[i] = fmt.Sprintf("%s.%s AS %s", j.ref(), column, j.ColumnAlias(column))
+	}
+	return columns
+}
+
+// ScanExtra collect the joined columns of the row to Rows
+func (j *Join) ScanExtra(values map[string]interface{}) {
+	row := make(map[string]interface{}, len(j.Columns))
+	for _, column := range j.Columns {
+		alias := j.ColumnAlias(column)
+		row[alias] = values[alias]
+	}
+	j.Rows = append(j.Rows, row)
+}
+
+// ColumnAlias return alias of joined column
+func (j *Join) ColumnAlias(column string) string {
+	return fmt.Sprintf("%s_%s", j.ref(), column)
+}
+
+func (j *Join) ref() string {
+	if j.Alias != "" {
+		return j.Alias
+	}
+	return j.Table
+}
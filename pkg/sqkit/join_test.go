@@ -0,0 +1,75 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestJoin(t *testing.T) {
+	testcases := []struct {
+		testName string
+		*sqkit.Join
+		builder       sq.SelectBuilder
+		expectedQuery string
+	}{
+		{
+			testName: "inner join",
+			Join: &sqkit.Join{
+				Table:   "publishers",
+				On:      "publishers.id = books.publisher_id",
+				Columns: []string{"name"},
+			},
+			builder:       sq.Select("books.id").From("books"),
+			expectedQuery: "SELECT books.id FROM books JOIN publishers ON publishers.id = books.publisher_id",
+		},
+		{
+			testName: "left join with alias",
+			Join: &sqkit.Join{
+				Left:    true,
+				Table:   "publishers",
+				Alias:   "p",
+				On:      "p.id = books.publisher_id",
+				Columns: []string{"id", "name"},
+			},
+			builder:       sq.Select("books.id").From("books"),
+			expectedQuery: "SELECT books.id FROM books LEFT JOIN publishers p ON p.id = books.publisher_id",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			query, _, err := tt.CompileSelect(tt.builder).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+		})
+	}
+}
+
+func TestSelectExtra(t *testing.T) {
+	opts := []sqkit.SelectOption{
+		&sqkit.Join{Table: "publishers", Columns: []string{"name"}},
+		sqkit.Eq{"id": 1},
+		&sqkit.Join{Table: "authors", Alias: "a", Columns: []string{"id", "name"}},
+	}
+	require.Equal(t, []string{
+		"publishers.name AS publishers_name",
+		"a.id AS a_id",
+		"a.name AS a_name",
+	}, sqkit.SelectExtra(opts))
+	require.Nil(t, sqkit.SelectExtra([]sqkit.SelectOption{sqkit.Eq{"id": 1}}))
+}
+
+func TestScanExtra(t *testing.T) {
+	join := &sqkit.Join{Table: "publishers", Alias: "p", Columns: []string{"id", "name"}}
+	id, name, other := interface{}(int64(1)), interface{}([]byte("some-name")), interface{}("other")
+	extra := map[string]interface{}{"p_id": &id, "p_name": &name, "other": &other}
+
+	sqkit.ScanExtra([]sqkit.SelectOption{join, sqkit.Eq{"id": 1}}, extra)
+	sqkit.ScanExtra([]sqkit.SelectOption{join}, map[string]interface{}{})
+	require.Equal(t, []map[string]interface{}{
+		{"p_id": int64(1), "p_name": "some-name"},
+		{"p_id": nil, "p_name": nil},
+	}, join.Rows)
+}
@@ -0,0 +1,159 @@
+package sqkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+type (
+	// JSONContains filter postgres jsonb column which contain the value
+	// (e.g. `metadata @> '{"tag":"go"}'`)
+	JSONContains struct {
+		Column string
+		Value  interface{} // marshalled to json
+	}
+	// JSONHasKey filter postgres jsonb column which has the top-level keys
+	// with `?` for single key, `?|` for any keys and `?&` for all keys
+	JSONHasKey struct {
+		Column string
+		Keys   []string
+		Any    bool
+	}
+	// JSONPath compare the text value of postgres jsonb path with `->>`
+	// for single key or `#>>` for nested path
+	JSONPath struct {
+		Column string
+		Path   []string
+		Op     string // comparison operator, by default is `=`
+		Cast   string // cast the text value (e.g. `numeric`)
+		Value  interface{}
+	}
+	// JSONSet partially update postgres jsonb column with `jsonb_set`
+	JSONSet struct {
+		Column        string
+		Path          []string
+		Value         interface{} // marshalled to json
+		CreateMissing bool
+	}
+)
+
+var _ SelectOption = (*JSONContains)(nil)
+var _ SelectOption = (*JSONHasKey)(nil)
+var _ SelectOption = (*JSONPath)(nil)
+var _ UpdateOption = (*JSONSet)(nil)
+
+var (
+	jsonPathOps = map[string]bool{
+		"=": true, "<>": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true,
+		"LIKE": true, "ILIKE": true,
+	}
+	jsonCast = regexp.MustCompile(`^[a-z][a-z0-9_ ]*$`)
+)
+
+//
+// JSONContains
+//
+
+// ToSql return `column @> ?::jsonb` condition
+func (j *JSONContains) ToSql() (string, []interface{}, error) {
+	b, err := json.Marshal(j.Value)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s @> ?::jsonb", j.Column), []interface{}{string(b)}, nil
+}
+
+// CompileSelect to compile select query for jsonb containment
+func (j *JSONContains) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	return base.Where(j)
+}
+
+//
+// JSONHasKey
+//
+
+// ToSql return key existence condition. The `?` operator is escaped as `??`.
+func (j *JSONHasKey) ToSql() (string, []interface{}, error) {
+	switch {
+	case len(j.Keys) < 1:
+		return "", nil, errors.New("sqkit: missing json keys")
+	case len(j.Keys) == 1:
+		return fmt.Sprintf("%s ?? ?", j.Column), []interface{}{j.Keys[0]}, nil
+	case j.Any:
+		return fmt.Sprintf("%s ??| ?", j.Column), []interface{}{pq.Array(j.Keys)}, nil
+	default:
+		return fmt.Sprintf("%s ??& ?", j.Column), []interface{}{pq.Array(j.Keys)}, nil
+	}
+}
+
+// CompileSelect to compile select query for jsonb key existence
+func (j *JSONHasKey) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	return base.Where(j)
+}
+
+//
+// JSONPath
+//
+
+// ToSql return path comparison condition
+func (j *JSONPath) ToSql() (string, []interface{}, error) {
+	op := j.Op
+	if op == "" {
+		op = "="
+	}
+	if !jsonPathOps[op] {
+		return "", nil, fmt.Errorf("sqkit: unknown json path operator '%s'", op)
+	}
+	if j.Cast != "" && !jsonCast.MatchString(j.Cast) {
+		return "", nil, fmt.Errorf("sqkit: invalid json path cast '%s'", j.Cast)
+	}
+
+	var (
+		extract string
+		args    []interface{}
+	)
+	switch len(j.Path) {
+	case 0:
+		return "", nil, errors.New("sqkit: missing json path")
+	case 1:
+		extract = fmt.Sprintf("%s ->> ?", j.Column)
+		args = append(args, j.Path[0])
+	default:
+		extract = fmt.Sprintf("%s #>> ?", j.Column)
+		args = append(args, pq.Array(j.Path))
+	}
+	extract = fmt.Sprintf("(%s)", extract)
+	if j.Cast != "" {
+		extract = fmt.Sprintf("%s::%s", extract, j.Cast)
+	}
+	return fmt.Sprintf("%s %s ?", extract, op), append(args, j.Value), nil
+}
+
+// CompileSelect to compile select query for jsonb path comparison
+func (j *JSONPath) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	return base.Where(j)
+}
+
+//
+// JSONSet
+//
+
+// CompileUpdate to compile update query for jsonb partial update
+func (j *JSONSet) CompileUpdate(base sq.UpdateBuilder) sq.UpdateBuilder {
+	if len(j.Path) < 1 {
+		return base.Set(j.Column, errSqlizer{err: errors.New("sqkit: missing json path")})
+	}
+	b, err := json.Marshal(j.Value)
+	if err != nil {
+		return base.Set(j.Column, errSqlizer{err: err})
+	}
+	return base.Set(j.Column, sq.Expr(
+		fmt.Sprintf("jsonb_set(%s, ?, ?::jsonb, ?)", j.Column),
+		pq.Array(j.Path), string(b), j.CreateMissing,
+	))
+}
@@ -0,0 +1,130 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestJSON_CompileSelect(t *testing.T) {
+	testcases := []struct {
+		testName      string
+		opt           sqkit.SelectOption
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   string
+	}{
+		{
+			testName:      "contains",
+			opt:           &sqkit.JSONContains{Column: "metadata", Value: map[string]string{"tag": "go"}},
+			expectedQuery: "SELECT id FROM books WHERE metadata @> $1::jsonb",
+			expectedArgs:  []interface{}{`{"tag":"go"}`},
+		},
+		{
+			testName:    "contains with invalid value",
+			opt:         &sqkit.JSONContains{Column: "metadata", Value: func() {}},
+			expectedErr: "json: unsupported type: func()",
+		},
+		{
+			testName:      "has key",
+			opt:           &sqkit.JSONHasKey{Column: "metadata", Keys: []string{"tag"}},
+			expectedQuery: "SELECT id FROM books WHERE metadata ? $1",
+			expectedArgs:  []interface{}{"tag"},
+		},
+		{
+			testName:      "has any keys",
+			opt:           &sqkit.JSONHasKey{Column: "metadata", Keys: []string{"tag", "isbn"}, Any: true},
+			expectedQuery: "SELECT id FROM books WHERE metadata ?| $1",
+			expectedArgs:  []interface{}{pq.Array([]string{"tag", "isbn"})},
+		},
+		{
+			testName:      "has all keys",
+			opt:           &sqkit.JSONHasKey{Column: "metadata", Keys: []string{"tag", "isbn"}},
+			expectedQuery: "SELECT id FROM books WHERE metadata ?& $1",
+			expectedArgs:  []interface{}{pq.Array([]string{"tag", "isbn"})},
+		},
+		{
+			testName:    "has no key",
+			opt:         &sqkit.JSONHasKey{Column: "metadata"},
+			expectedErr: "sqkit: missing json keys",
+		},
+		{
+			testName:      "path",
+			opt:           &sqkit.JSONPath{Column: "metadata", Path: []string{"tag"}, Value: "go"},
+			expectedQuery: "SELECT id FROM books WHERE (metadata ->> $1) = $2",
+			expectedArgs:  []interface{}{"tag", "go"},
+		},
+		{
+			testName:      "nested path with cast",
+			opt:           &sqkit.JSONPath{Column: "metadata", Path: []string{"stock", "count"}, Op: ">", Cast: "numeric", Value: 10},
+			expectedQuery: "SELECT id FROM books WHERE (metadata #>> $1)::numeric > $2",
+			expectedArgs:  []interface{}{pq.Array([]string{"stock", "count"}), 10},
+		},
+		{
+			testName:    "path with unknown operator",
+			opt:         &sqkit.JSONPath{Column: "metadata", Path: []string{"tag"}, Op: "; DROP", Value: "go"},
+			expectedErr: "sqkit: unknown json path operator '; DROP'",
+		},
+		{
+			testName:    "path with invalid cast",
+			opt:         &sqkit.JSONPath{Column: "metadata", Path: []string{"tag"}, Cast: "int;", Value: 1},
+			expectedErr: "sqkit: invalid json path cast 'int;'",
+		},
+		{
+			testName:    "missing path",
+			opt:         &sqkit.JSONPath{Column: "metadata", Value: "go"},
+			expectedErr: "sqkit: missing json path",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := sq.Select("id").From("books").PlaceholderFormat(sq.Dollar)
+			query, args, err := tt.opt.CompileSelect(builder).ToSql()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestJSONSet_CompileUpdate(t *testing.T) {
+	testcases := []struct {
+		testName string
+		*sqkit.JSONSet
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   string
+	}{
+		{
+			testName:      "jsonb set",
+			JSONSet:       &sqkit.JSONSet{Column: "metadata", Path: []string{"stock", "count"}, Value: 5, CreateMissing: true},
+			expectedQuery: "UPDATE books SET metadata = jsonb_set(metadata, $1, $2::jsonb, $3) WHERE id = $4",
+			expectedArgs:  []interface{}{pq.Array([]string{"stock", "count"}), "5", true, 1},
+		},
+		{
+			testName:    "missing path",
+			JSONSet:     &sqkit.JSONSet{Column: "metadata", Value: 5},
+			expectedErr: "sqkit: missing json path",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := sq.Update("books").Where(sq.Eq{"id": 1}).PlaceholderFormat(sq.Dollar)
+			query, args, err := tt.CompileUpdate(builder).ToSql()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package sqkit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// Lock selected rows until the end of transaction (e.g. `FOR UPDATE SKIP
+	// LOCKED` for job queue). The lock is released right away without
+	// transaction, so generated repository Find return ErrLockWithoutTxn
+	// when the context is not begun by `dbtxn.Begin`.
+	Lock struct {
+		Dialect  Dialect
+		Strength LockStrength
+		Wait     LockWait
+		Of       []string // lock only rows of the tables
+	}
+	// LockStrength of row lock
+	LockStrength int
+	// LockWait behavior when the row is locked by other transaction
+	LockWait int
+)
+
+const (
+	// ForUpdate lock for update
+	ForUpdate LockStrength = iota
+	// ForShare lock for read
+	ForShare
+	// ForNoKeyUpdate weaker update lock which not block `FOR KEY SHARE` (postgres only)
+	ForNoKeyUpdate
+	// ForKeyShare weaker share lock which block only `FOR UPDATE` (postgres only)
+	ForKeyShare
+)
+
+const (
+	// Wait until the lock released
+	Wait LockWait = iota
+	// NoWait return error immediately
+	NoWait
+	// SkipLocked skip the locked rows
+	SkipLocked
+)
+
+var _ SelectOption = (*Lock)(nil)
+
+// ErrLockWithoutTxn is error when lock the rows outside transaction
+var ErrLockWithoutTxn = errors.New("sqkit: lock require transaction")
+
+// HasLock return true if there is Lock in the options
+func HasLock(opts []SelectOption) bool {
+	for _, opt := range opts {
+		if _, ok := opt.(*Lock); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CompileSelect to compile select query for row locking
+func (l *Lock) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	clause, err := l.clause()
+	if err != nil {
+		return base.Where(errSqlizer{err: err})
+	}
+	return base.Suffix(clause)
+}
+
+func (l *Lock) clause() (string, error) {
+	var clause string
+	switch l.Strength {
+	case ForUpdate:
+		clause = "FOR UPDATE"
+	case ForShare:
+		clause = "FOR SHARE"
+	case ForNoKeyUpdate:
+		clause = "FOR NO KEY UPDATE"
+	case ForKeyShare:
+		clause = "FOR KEY SHARE"
+	default:
+		return "", fmt.Errorf("sqkit: unknown lock strength %d", l.Strength)
+	}
+	if l.Dialect == MySQL && (l.Strength == ForNoKeyUpdate || l.Strength == ForKeyShare) {
+		return "", fmt.Errorf("sqkit: %s not support '%s'", l.Dialect, clause)
+	}
+
+	if len(l.Of) > 0 {
+		var tables []string
+		for _, table := range l.Of {
+			tables = append(tables, l.Dialect.Quote(table))
+		}
+		clause = clause + " OF " + strings.Join(tables, ", ")
+	}
+
+	switch l.Wait {
+	case Wait:
+	case NoWait:
+		clause = clause + " NOWAIT"
+	case SkipLocked:
+		clause = clause + " SKIP LOCKED"
+	default:
+		return "", fmt.Errorf("sqkit: unknown lock wait %d", l.Wait)
+	}
+	return clause, nil
+}
@@ -0,0 +1,67 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestLock(t *testing.T) {
+	testcases := []struct {
+		testName string
+		*sqkit.Lock
+		expectedQuery string
+		expectedErr   string
+	}{
+		{
+			testName:      "for update",
+			Lock:          &sqkit.Lock{},
+			expectedQuery: "SELECT id FROM jobs WHERE status = ? FOR UPDATE",
+		},
+		{
+			testName:      "postgres skip locked",
+			Lock:          &sqkit.Lock{Dialect: sqkit.Postgres, Wait: sqkit.SkipLocked, Of: []string{"jobs"}},
+			expectedQuery: `SELECT id FROM jobs WHERE status = ? FOR UPDATE OF "jobs" SKIP LOCKED`,
+		},
+		{
+			testName:      "postgres for no key update",
+			Lock:          &sqkit.Lock{Dialect: sqkit.Postgres, Strength: sqkit.ForNoKeyUpdate},
+			expectedQuery: "SELECT id FROM jobs WHERE status = ? FOR NO KEY UPDATE",
+		},
+		{
+			testName:      "mysql for share nowait",
+			Lock:          &sqkit.Lock{Dialect: sqkit.MySQL, Strength: sqkit.ForShare, Wait: sqkit.NoWait},
+			expectedQuery: "SELECT id FROM jobs WHERE status = ? FOR SHARE NOWAIT",
+		},
+		{
+			testName:    "mysql for key share",
+			Lock:        &sqkit.Lock{Dialect: sqkit.MySQL, Strength: sqkit.ForKeyShare},
+			expectedErr: "sqkit: mysql not support 'FOR KEY SHARE'",
+		},
+		{
+			testName:    "unknown wait",
+			Lock:        &sqkit.Lock{Wait: 99},
+			expectedErr: "sqkit: unknown lock wait 99",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := sq.Select("id").From("jobs").Where(sq.Eq{"status": "pending"})
+			query, args, err := tt.CompileSelect(builder).ToSql()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, []interface{}{"pending"}, args)
+		})
+	}
+}
+
+func TestHasLock(t *testing.T) {
+	require.True(t, sqkit.HasLock([]sqkit.SelectOption{sqkit.Eq{"id": 1}, &sqkit.Lock{}}))
+	require.False(t, sqkit.HasLock([]sqkit.SelectOption{sqkit.Eq{"id": 1}}))
+}
@@ -0,0 +1,30 @@
+package sqkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type (
+	// NotFoundError is error when no row found (e.g. by `FindOne` of the
+	// generated repository)
+	NotFoundError struct {
+		Table string
+	}
+)
+
+// IsNotFound return true if the error is NotFoundError
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found", e.Table)
+}
+
+// HTTPStatus of not found error
+func (e *NotFoundError) HTTPStatus() int {
+	return http.StatusNotFound
+}
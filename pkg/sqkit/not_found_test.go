@@ -0,0 +1,24 @@
+package sqkit_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/echokit"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestNotFoundError(t *testing.T) {
+	err := &sqkit.NotFoundError{Table: "books"}
+	require.EqualError(t, err, "books not found")
+	require.True(t, sqkit.IsNotFound(err))
+	require.True(t, sqkit.IsNotFound(fmt.Errorf("wrapped: %w", err)))
+	require.False(t, sqkit.IsNotFound(errors.New("some-error")))
+
+	httpErr := echokit.HTTPError(err)
+	require.Equal(t, http.StatusNotFound, httpErr.Code)
+	require.Equal(t, "books not found", httpErr.Message)
+}
@@ -0,0 +1,68 @@
+package sqkit
+
+import (
+	"fmt"
+	"net/http"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// OptimisticLock update only when version column is match with Version
+	// and increment the version. Use Timestamp for timestamp column (e.g.
+	// updated_at) which is already set to now by the repository.
+	OptimisticLock struct {
+		Column    string // by default is `version`
+		Version   interface{}
+		Timestamp bool
+	}
+	// ConflictError is error of outdated version
+	ConflictError struct {
+		Column  string
+		Version interface{}
+	}
+)
+
+var _ UpdateOption = (*OptimisticLock)(nil)
+
+// CompileUpdate to compile update query for optimistic locking
+func (o *OptimisticLock) CompileUpdate(base sq.UpdateBuilder) sq.UpdateBuilder {
+	column := o.column()
+	base = base.Where(sq.Eq{column: o.Version})
+	if !o.Timestamp {
+		base = base.Set(column, sq.Expr(column+" + 1"))
+	}
+	return base
+}
+
+// Check return ConflictError when no affected row of update (e.g.
+// `lock.Check(repo.Update(ctx, ent, lock))`)
+func (o *OptimisticLock) Check(affectedRow int64, err error) error {
+	if err != nil {
+		return err
+	}
+	if affectedRow < 1 {
+		return &ConflictError{Column: o.column(), Version: o.Version}
+	}
+	return nil
+}
+
+func (o *OptimisticLock) column() string {
+	if o.Column == "" {
+		return "version"
+	}
+	return o.Column
+}
+
+//
+// ConflictError
+//
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("sqkit: conflict on outdated %s '%v'", e.Column, e.Version)
+}
+
+// HTTPStatus of conflict error
+func (e *ConflictError) HTTPStatus() int {
+	return http.StatusConflict
+}
@@ -0,0 +1,87 @@
+package sqkit_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/echokit"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestOptimisticLock_CompileUpdate(t *testing.T) {
+	updatedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		testName string
+		*sqkit.OptimisticLock
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			testName:       "version",
+			OptimisticLock: &sqkit.OptimisticLock{Version: 3},
+			expectedQuery:  "UPDATE books SET title = ?, version = version + 1 WHERE id = ? AND version = ?",
+			expectedArgs:   []interface{}{"some-title", 1, 3},
+		},
+		{
+			testName:       "timestamp",
+			OptimisticLock: &sqkit.OptimisticLock{Column: "updated_at", Version: updatedAt, Timestamp: true},
+			expectedQuery:  "UPDATE books SET title = ? WHERE id = ? AND updated_at = ?",
+			expectedArgs:   []interface{}{"some-title", 1, updatedAt},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := sq.Update("books").Set("title", "some-title").Where(sq.Eq{"id": 1})
+			query, args, err := tt.CompileUpdate(builder).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestOptimisticLock_Check(t *testing.T) {
+	lock := &sqkit.OptimisticLock{Version: 3}
+	testcases := []struct {
+		testName    string
+		affectedRow int64
+		err         error
+		expectedErr string
+	}{
+		{
+			testName:    "updated",
+			affectedRow: 1,
+		},
+		{
+			testName:    "update error",
+			affectedRow: -1,
+			err:         errors.New("some-error"),
+			expectedErr: "some-error",
+		},
+		{
+			testName:    "conflict",
+			affectedRow: 0,
+			expectedErr: "sqkit: conflict on outdated version '3'",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := lock.Check(tt.affectedRow, tt.err)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	err := (&sqkit.OptimisticLock{Version: 3}).Check(0, nil)
+	require.IsType(t, &sqkit.ConflictError{}, err)
+	require.Equal(t, http.StatusConflict, echokit.HTTPError(err).Code)
+}
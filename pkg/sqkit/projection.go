@@ -0,0 +1,56 @@
+package sqkit
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// Projection select the subset of columns. It is applied by the repository
+	// to its column list (see SelectColumns), so the count and exists query
+	// are not affected.
+	Projection []string
+)
+
+var _ SelectOption = (Projection)(nil)
+
+// ParseProjection parse comma separated fields (e.g. `id,title`) against
+// allowed columns
+func ParseProjection(raw string, columns Columns) (Projection, error) {
+	var projection Projection
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		column, ok := columns.Column(field)
+		if !ok {
+			return nil, fmt.Errorf("sqkit: unknown field '%s'", field)
+		}
+		if !seen[column] {
+			seen[column] = true
+			projection = append(projection, column)
+		}
+	}
+	return projection, nil
+}
+
+// CompileSelect return the base as is. The projection is applied by
+// SelectColumns when building the select query.
+func (p Projection) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	return base
+}
+
+// SelectColumns return columns of the last non-empty Projection in the
+// options or the default columns when there is no projection
+func SelectColumns(columns []string, opts []SelectOption) []string {
+	for i := len(opts) - 1; i >= 0; i-- {
+		if p, ok := opts[i].(Projection); ok && len(p) > 0 {
+			return p
+		}
+	}
+	return columns
+}
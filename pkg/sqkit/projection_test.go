@@ -0,0 +1,86 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestParseProjection(t *testing.T) {
+	columns := sqkit.Columns{"id": "id", "title": "title", "author": "author"}
+	testcases := []struct {
+		testName    string
+		raw         string
+		expected    sqkit.Projection
+		expectedErr string
+	}{
+		{
+			testName: "empty",
+		},
+		{
+			testName: "fields",
+			raw:      " id, title,,id",
+			expected: sqkit.Projection{"id", "title"},
+		},
+		{
+			testName:    "unknown field",
+			raw:         "id,password",
+			expectedErr: "sqkit: unknown field 'password'",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			projection, err := sqkit.ParseProjection(tt.raw, columns)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, projection)
+		})
+	}
+}
+
+func TestProjection_CompileSelect(t *testing.T) {
+	builder := sq.Select("1").From("books").Where(sq.Eq{"id": 1})
+	query, _, err := sqkit.Projection{"id", "title"}.CompileSelect(builder).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT 1 FROM books WHERE id = ?", query)
+}
+
+func TestSelectColumns(t *testing.T) {
+	columns := []string{"id", "title", "author"}
+	testcases := []struct {
+		testName string
+		opts     []sqkit.SelectOption
+		expected []string
+	}{
+		{
+			testName: "no projection",
+			opts:     []sqkit.SelectOption{sqkit.Eq{"id": 1}},
+			expected: []string{"id", "title", "author"},
+		},
+		{
+			testName: "empty projection",
+			opts:     []sqkit.SelectOption{sqkit.Projection{}},
+			expected: []string{"id", "title", "author"},
+		},
+		{
+			testName: "projection",
+			opts:     []sqkit.SelectOption{sqkit.Eq{"id": 1}, sqkit.Projection{"id", "title"}},
+			expected: []string{"id", "title"},
+		},
+		{
+			testName: "last projection",
+			opts:     []sqkit.SelectOption{sqkit.Projection{"id"}, sqkit.Projection{"title"}},
+			expected: []string{"title"},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			require.Equal(t, tt.expected, sqkit.SelectColumns(columns, tt.opts))
+		})
+	}
+}
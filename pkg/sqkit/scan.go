@@ -0,0 +1,102 @@
+package sqkit
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ScanMaps scan rows to maps keyed by column name
+func ScanMaps(rows *sql.Rows) ([]map[string]interface{}, error) {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				m[column] = string(b)
+			} else {
+				m[column] = values[i]
+			}
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// ScanStructs scan rows to dest which is pointer to slice of struct or
+// struct pointer. Struct field is mapped by `column` tag or lowercase field
+// name, and column without field is discarded.
+func ScanStructs(rows *sql.Rows, dest interface{}) error {
+	defer rows.Close()
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return errors.New("sqkit: scan destination must be pointer of slice")
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.New("sqkit: scan destination must be slice of struct")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	fieldIndexes := structFieldIndexes(elemType)
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		dest := make([]interface{}, len(columns))
+		for i, column := range columns {
+			if index, ok := fieldIndexes[column]; ok {
+				dest[i] = elem.Elem().Field(index).Addr().Interface()
+			} else {
+				dest[i] = new(interface{})
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	return rows.Err()
+}
+
+func structFieldIndexes(typ reflect.Type) map[string]int {
+	indexes := make(map[string]int)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" { // NOTE: unexported field
+			continue
+		}
+		column := field.Tag.Get("column")
+		if column == "" {
+			column = strings.ToLower(field.Name)
+		}
+		indexes[column] = i
+	}
+	return indexes
+}
@@ -0,0 +1,62 @@
+package sqkit_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestScanMaps(t *testing.T) {
+	rows := queryRows(t, sqlmock.NewRows([]string{"author", "total"}).
+		AddRow([]byte("author-1"), 2).
+		AddRow("author-2", 3))
+
+	list, err := sqkit.ScanMaps(rows)
+	require.NoError(t, err)
+	require.Equal(t, []map[string]interface{}{
+		{"author": "author-1", "total": int64(2)},
+		{"author": "author-2", "total": int64(3)},
+	}, list)
+}
+
+func TestScanStructs(t *testing.T) {
+	type report struct {
+		Author string `column:"author"`
+		Total  int64
+	}
+	t.Run("slice of struct pointer", func(t *testing.T) {
+		rows := queryRows(t, sqlmock.NewRows([]string{"author", "total", "unknown"}).
+			AddRow("author-1", 2, "x").
+			AddRow("author-2", 3, "y"))
+		var reports []*report
+		require.NoError(t, sqkit.ScanStructs(rows, &reports))
+		require.Equal(t, []*report{{Author: "author-1", Total: 2}, {Author: "author-2", Total: 3}}, reports)
+	})
+	t.Run("slice of struct", func(t *testing.T) {
+		rows := queryRows(t, sqlmock.NewRows([]string{"author"}).AddRow("author-1"))
+		var reports []report
+		require.NoError(t, sqkit.ScanStructs(rows, &reports))
+		require.Equal(t, []report{{Author: "author-1"}}, reports)
+	})
+	t.Run("not pointer of slice", func(t *testing.T) {
+		rows := queryRows(t, sqlmock.NewRows([]string{"author"}))
+		var reports []report
+		require.EqualError(t, sqkit.ScanStructs(rows, reports), "sqkit: scan destination must be pointer of slice")
+	})
+	t.Run("not slice of struct", func(t *testing.T) {
+		rows := queryRows(t, sqlmock.NewRows([]string{"author"}))
+		var authors []string
+		require.EqualError(t, sqkit.ScanStructs(rows, &authors), "sqkit: scan destination must be slice of struct")
+	})
+}
+
+func queryRows(t *testing.T, mockRows *sqlmock.Rows) *sql.Rows {
+	db, mock, _ := sqlmock.New()
+	mock.ExpectQuery("SELECT").WillReturnRows(mockRows)
+	rows, err := db.Query("SELECT")
+	require.NoError(t, err)
+	return rows
+}
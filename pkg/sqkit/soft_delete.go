@@ -0,0 +1,100 @@
+package sqkit
+
+import (
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// SoftDelete scope of soft deleted row which has not null deleted
+	// column. By default is exclude the soft deleted row.
+	SoftDelete struct {
+		Column string // by default is `deleted_at`
+		Scope  SoftDeleteScope
+	}
+	// SoftDeleteScope scope of soft deleted row
+	SoftDeleteScope int
+)
+
+// DeletedAt is default soft delete column
+const DeletedAt = "deleted_at"
+
+const (
+	// ExcludeDeleted exclude soft deleted row
+	ExcludeDeleted SoftDeleteScope = iota
+	// IncludeDeleted include soft deleted row
+	IncludeDeleted
+	// OnlyDeleted only soft deleted row
+	OnlyDeleted
+)
+
+var _ SelectOption = (*SoftDelete)(nil)
+var _ UpdateOption = (*SoftDelete)(nil)
+
+// WithSoftDelete return options with default SoftDelete of column when
+// there is no SoftDelete in the options
+func WithSoftDelete(column string, opts []SelectOption) []SelectOption {
+	for i, opt := range opts {
+		if s, ok := opt.(*SoftDelete); ok {
+			if s.Column == "" {
+				opts = append([]SelectOption{}, opts...)
+				opts[i] = &SoftDelete{Column: column, Scope: s.Scope}
+			}
+			return opts
+		}
+	}
+	return append([]SelectOption{&SoftDelete{Column: column}}, opts...)
+}
+
+// CompileSelect to compile select query for soft delete scope
+func (s *SoftDelete) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	if cond := s.cond(); cond != nil {
+		return base.Where(cond)
+	}
+	return base
+}
+
+// CompileUpdate to compile update query for soft delete scope
+func (s *SoftDelete) CompileUpdate(base sq.UpdateBuilder) sq.UpdateBuilder {
+	if cond := s.cond(); cond != nil {
+		return base.Where(cond)
+	}
+	return base
+}
+
+// Delete return soft delete query `UPDATE table SET deleted_at = CURRENT_TIMESTAMP`
+// with the delete option compiled as UpdateOption (e.g. Eq, Where or Filter).
+// Soft deleted row is not updated. Return error when the option is not
+// UpdateOption.
+func (s *SoftDelete) Delete(table string, opt DeleteOption) (sq.UpdateBuilder, error) {
+	update := sq.
+		Update(table).
+		Set(s.column(), sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{s.column(): nil})
+	if opt == nil {
+		return update, nil
+	}
+	updateOpt, ok := opt.(UpdateOption)
+	if !ok {
+		return update, fmt.Errorf("sqkit: soft delete require UpdateOption but got %T", opt)
+	}
+	return updateOpt.CompileUpdate(update), nil
+}
+
+func (s *SoftDelete) cond() sq.Sqlizer {
+	switch s.Scope {
+	case ExcludeDeleted:
+		return sq.Eq{s.column(): nil}
+	case OnlyDeleted:
+		return sq.NotEq{s.column(): nil}
+	}
+	return nil
+}
+
+func (s *SoftDelete) column() string {
+	if s.Column == "" {
+		return DeletedAt
+	}
+	return s.Column
+}
@@ -0,0 +1,122 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestSoftDelete_CompileSelect(t *testing.T) {
+	testcases := []struct {
+		testName string
+		*sqkit.SoftDelete
+		expectedQuery string
+	}{
+		{
+			testName:      "exclude deleted",
+			SoftDelete:    &sqkit.SoftDelete{},
+			expectedQuery: "SELECT id FROM books WHERE deleted_at IS NULL",
+		},
+		{
+			testName:      "include deleted",
+			SoftDelete:    &sqkit.SoftDelete{Scope: sqkit.IncludeDeleted},
+			expectedQuery: "SELECT id FROM books",
+		},
+		{
+			testName:      "only deleted",
+			SoftDelete:    &sqkit.SoftDelete{Column: "removed_at", Scope: sqkit.OnlyDeleted},
+			expectedQuery: "SELECT id FROM books WHERE removed_at IS NOT NULL",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			query, _, err := tt.CompileSelect(sq.Select("id").From("books")).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+		})
+	}
+}
+
+func TestSoftDelete_CompileUpdate(t *testing.T) {
+	softDelete := &sqkit.SoftDelete{}
+	query, args, err := softDelete.CompileUpdate(sq.Update("books").Set("title", "some-title")).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE books SET title = ? WHERE deleted_at IS NULL", query)
+	require.Equal(t, []interface{}{"some-title"}, args)
+}
+
+func TestSoftDelete_Delete(t *testing.T) {
+	testcases := []struct {
+		testName      string
+		opt           sqkit.DeleteOption
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   string
+	}{
+		{
+			testName:      "no option",
+			expectedQuery: "UPDATE books SET deleted_at = CURRENT_TIMESTAMP WHERE deleted_at IS NULL",
+		},
+		{
+			testName:      "with filter",
+			opt:           sqkit.Where{sq.Eq{"id": 1}, sq.Lt{"version": 3}},
+			expectedQuery: "UPDATE books SET deleted_at = CURRENT_TIMESTAMP WHERE deleted_at IS NULL AND id = $1 AND version < $2",
+			expectedArgs:  []interface{}{1, 3},
+		},
+		{
+			testName:      "with eq",
+			opt:           sqkit.Eq{"id": 1},
+			expectedQuery: "UPDATE books SET deleted_at = CURRENT_TIMESTAMP WHERE deleted_at IS NULL AND id = $1",
+			expectedArgs:  []interface{}{1},
+		},
+		{
+			testName: "not update option",
+			opt: sqkit.NewDeleteOption(func(b sq.DeleteBuilder) sq.DeleteBuilder {
+				return b.Limit(1)
+			}),
+			expectedErr: "sqkit: soft delete require UpdateOption but got *sqkit.deleteOptionImpl",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			softDelete := &sqkit.SoftDelete{}
+			update, err := softDelete.Delete("books", tt.opt)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			query, args, err := update.PlaceholderFormat(sq.Dollar).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestWithSoftDelete(t *testing.T) {
+	eq := sqkit.Eq{"id": 1}
+	testcases := []struct {
+		testName string
+		opts     []sqkit.SelectOption
+		expected []sqkit.SelectOption
+	}{
+		{
+			testName: "default soft delete",
+			opts:     []sqkit.SelectOption{eq},
+			expected: []sqkit.SelectOption{&sqkit.SoftDelete{Column: "removed_at"}, eq},
+		},
+		{
+			testName: "soft delete in options",
+			opts:     []sqkit.SelectOption{eq, &sqkit.SoftDelete{Scope: sqkit.OnlyDeleted}},
+			expected: []sqkit.SelectOption{eq, &sqkit.SoftDelete{Column: "removed_at", Scope: sqkit.OnlyDeleted}},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			require.Equal(t, tt.expected, sqkit.WithSoftDelete("removed_at", tt.opts))
+		})
+	}
+}
@@ -9,34 +9,125 @@ import (
 
 type (
 	// Sorts sorting
-	Sorts []string
+	Sorts []Sort
+	// Sort by column. Column is quoted by Dialect.
+	Sort struct {
+		Column  string
+		Desc    bool
+		Nulls   Nulls
+		Dialect Dialect
+	}
+	// Nulls ordering of null value
+	Nulls int
+	// SortError is error of unknown sort column
+	SortError struct {
+		Field string
+	}
+)
+
+const (
+	// NullsDefault follow database default
+	NullsDefault Nulls = iota
+	// NullsFirst order null value first
+	NullsFirst
+	// NullsLast order null value last
+	NullsLast
 )
 
 //
 // Sort
 //
 
-var _ SelectOption = (*Sorts)(nil)
+var _ SelectOption = (Sorts)(nil)
+
+// ParseSorts parse comma separated fields (e.g. `-title:nulls_last,+id`)
+// against allowed columns. Prefix `-` for descending order and suffix
+// `:nulls_first` or `:nulls_last` for null ordering.
+func ParseSorts(raw string, columns Columns, dialect Dialect) (Sorts, error) {
+	var sorts Sorts
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		sort := Sort{Dialect: dialect}
+		if strings.HasPrefix(s, "-") {
+			s, sort.Desc = s[1:], true
+		} else if strings.HasPrefix(s, "+") {
+			s = s[1:]
+		}
+		if i := strings.Index(s, ":"); i >= 0 {
+			switch s[i+1:] {
+			case "nulls_first":
+				sort.Nulls = NullsFirst
+			case "nulls_last":
+				sort.Nulls = NullsLast
+			default:
+				return nil, &SortError{Field: s}
+			}
+			s = s[:i]
+		}
+		column, ok := columns.Column(s)
+		if !ok {
+			return nil, &SortError{Field: s}
+		}
+		sort.Column = column
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
 
 // CompileSelect to compile select query for sorting
 func (s Sorts) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
 	for _, sort := range s {
-		base = base.OrderBy(s.statement(sort))
+		base = base.OrderBy(sort.statements()...)
 	}
 	return base
 }
 
-func (s Sorts) statement(sort string) string {
-	var column, orderBy string
-	if strings.HasPrefix(sort, "-") {
-		column = sort[1:]
+// reverse the direction and null ordering of sorts
+func (s Sorts) reverse() Sorts {
+	reversed := make(Sorts, len(s))
+	for i, sort := range s {
+		sort.Desc = !sort.Desc
+		switch sort.Nulls {
+		case NullsFirst:
+			sort.Nulls = NullsLast
+		case NullsLast:
+			sort.Nulls = NullsFirst
+		}
+		reversed[i] = sort
+	}
+	return reversed
+}
+
+func (s Sort) statements() []string {
+	column := s.Dialect.Quote(s.Column)
+	orderBy := "ASC"
+	if s.Desc {
 		orderBy = "DESC"
-	} else if strings.HasPrefix(sort, "+") {
-		column = sort[1:]
-		orderBy = "ASC"
-	} else {
-		column = sort
-		orderBy = "ASC"
-	}
-	return fmt.Sprintf("%s %s", column, orderBy)
+	}
+	statement := fmt.Sprintf("%s %s", column, orderBy)
+	if s.Nulls == NullsDefault {
+		return []string{statement}
+	}
+	if s.Dialect == MySQL {
+		// NOTE: mysql not support `NULLS FIRST/LAST`
+		if s.Nulls == NullsFirst {
+			return []string{column + " IS NULL DESC", statement}
+		}
+		return []string{column + " IS NULL ASC", statement}
+	}
+	if s.Nulls == NullsFirst {
+		return []string{statement + " NULLS FIRST"}
+	}
+	return []string{statement + " NULLS LAST"}
+}
+
+//
+// SortError
+//
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("sqkit: unknown sort column '%s'", e.Field)
 }
@@ -22,10 +22,32 @@ func TestSort(t *testing.T) {
 			expectedQuery: "SELECT col1, col2, col3 FROM sometables",
 		},
 		{
-			sorts:         sqkit.Sorts{"col1", "+col2", "-col3"},
+			sorts: sqkit.Sorts{
+				{Column: "col1"},
+				{Column: "col2"},
+				{Column: "col3", Desc: true},
+			},
 			builder:       sq.Select("col1", "col2", "col3").From("sometables"),
 			expectedQuery: "SELECT col1, col2, col3 FROM sometables ORDER BY col1 ASC, col2 ASC, col3 DESC",
 		},
+		{
+			testName: "postgres",
+			sorts: sqkit.Sorts{
+				{Column: "col1", Dialect: sqkit.Postgres, Nulls: sqkit.NullsFirst},
+				{Column: "col2", Dialect: sqkit.Postgres, Nulls: sqkit.NullsLast, Desc: true},
+			},
+			builder:       sq.Select("col1", "col2").From("sometables"),
+			expectedQuery: `SELECT col1, col2 FROM sometables ORDER BY "col1" ASC NULLS FIRST, "col2" DESC NULLS LAST`,
+		},
+		{
+			testName: "mysql",
+			sorts: sqkit.Sorts{
+				{Column: "col1", Dialect: sqkit.MySQL, Nulls: sqkit.NullsFirst},
+				{Column: "col2", Dialect: sqkit.MySQL, Nulls: sqkit.NullsLast, Desc: true},
+			},
+			builder:       sq.Select("col1", "col2").From("sometables"),
+			expectedQuery: "SELECT col1, col2 FROM sometables ORDER BY `col1` IS NULL DESC, `col1` ASC, `col2` IS NULL ASC, `col2` DESC",
+		},
 	}
 
 	for _, tt := range testcases {
@@ -36,3 +58,49 @@ func TestSort(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSorts(t *testing.T) {
+	columns := sqkit.Columns{"title": "title", "createdAt": "created_at"}
+	testcases := []struct {
+		testName    string
+		raw         string
+		expected    sqkit.Sorts
+		expectedErr string
+	}{
+		{
+			testName: "empty",
+			raw:      "",
+		},
+		{
+			raw: "title,+createdAt, -title:nulls_last,createdAt:nulls_first",
+			expected: sqkit.Sorts{
+				{Column: "title", Dialect: sqkit.Postgres},
+				{Column: "created_at", Dialect: sqkit.Postgres},
+				{Column: "title", Desc: true, Nulls: sqkit.NullsLast, Dialect: sqkit.Postgres},
+				{Column: "created_at", Nulls: sqkit.NullsFirst, Dialect: sqkit.Postgres},
+			},
+		},
+		{
+			testName:    "unknown column",
+			raw:         "title,name;DROP TABLE books",
+			expectedErr: "sqkit: unknown sort column 'name;DROP TABLE books'",
+		},
+		{
+			testName:    "unknown nulls",
+			raw:         "title:nulls_middle",
+			expectedErr: "sqkit: unknown sort column 'title:nulls_middle'",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			sorts, err := sqkit.ParseSorts(tt.raw, columns, sqkit.Postgres)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				require.IsType(t, &sqkit.SortError{}, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, sorts)
+		})
+	}
+}
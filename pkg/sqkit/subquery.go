@@ -0,0 +1,166 @@
+package sqkit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// Subquery is select query composed from select options. The subquery
+	// always compiled with `?` placeholder so that the outer query renumber
+	// the placeholders (e.g. `$n` for postgres).
+	Subquery struct {
+		Builder sq.SelectBuilder
+		Options []SelectOption
+	}
+	// In filter column by subquery `column IN (SELECT ...)`
+	In struct {
+		Column string
+		Not    bool // NOT IN
+		Subquery
+	}
+	// Exists filter by subquery `EXISTS (SELECT ...)`
+	Exists struct {
+		Not bool // NOT EXISTS
+		Subquery
+	}
+	// CTE is common table expression `name (columns) AS (SELECT ...)`
+	CTE struct {
+		Name    string
+		Columns []string
+		Subquery
+	}
+	// With common table expressions `WITH name AS (SELECT ...)`
+	With struct {
+		Recursive bool
+		CTEs      []CTE
+	}
+)
+
+var _ sq.Sqlizer = (*Subquery)(nil)
+var _ SelectOption = (*In)(nil)
+var _ UpdateOption = (*In)(nil)
+var _ DeleteOption = (*In)(nil)
+var _ SelectOption = (*Exists)(nil)
+var _ UpdateOption = (*Exists)(nil)
+var _ DeleteOption = (*Exists)(nil)
+var _ SelectOption = (*With)(nil)
+
+// ToSql return the subquery with `?` placeholder
+func (s *Subquery) ToSql() (string, []interface{}, error) {
+	builder := s.Builder
+	for _, opt := range s.Options {
+		builder = opt.CompileSelect(builder)
+	}
+	return builder.PlaceholderFormat(sq.Question).ToSql()
+}
+
+//
+// In
+//
+
+// ToSql return `column IN (SELECT ...)` condition
+func (i *In) ToSql() (string, []interface{}, error) {
+	if i.Column == "" {
+		return "", nil, errors.New("sqkit: missing subquery column")
+	}
+	query, args, err := i.Subquery.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	op := "IN"
+	if i.Not {
+		op = "NOT IN"
+	}
+	return fmt.Sprintf("%s %s (%s)", i.Column, op, query), args, nil
+}
+
+// CompileSelect to compile select query for subquery filter
+func (i *In) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	return base.Where(i)
+}
+
+// CompileUpdate to compile update query for subquery filter
+func (i *In) CompileUpdate(base sq.UpdateBuilder) sq.UpdateBuilder {
+	return base.Where(i)
+}
+
+// CompileDelete to compile delete query for subquery filter
+func (i *In) CompileDelete(base sq.DeleteBuilder) sq.DeleteBuilder {
+	return base.Where(i)
+}
+
+//
+// Exists
+//
+
+// ToSql return `EXISTS (SELECT ...)` condition
+func (e *Exists) ToSql() (string, []interface{}, error) {
+	query, args, err := e.Subquery.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	op := "EXISTS"
+	if e.Not {
+		op = "NOT EXISTS"
+	}
+	return fmt.Sprintf("%s (%s)", op, query), args, nil
+}
+
+// CompileSelect to compile select query for exists filter
+func (e *Exists) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	return base.Where(e)
+}
+
+// CompileUpdate to compile update query for exists filter
+func (e *Exists) CompileUpdate(base sq.UpdateBuilder) sq.UpdateBuilder {
+	return base.Where(e)
+}
+
+// CompileDelete to compile delete query for exists filter
+func (e *Exists) CompileDelete(base sq.DeleteBuilder) sq.DeleteBuilder {
+	return base.Where(e)
+}
+
+//
+// With
+//
+
+// ToSql return `WITH name AS (SELECT ...), ...` clause
+func (w *With) ToSql() (string, []interface{}, error) {
+	if len(w.CTEs) < 1 {
+		return "", nil, errors.New("sqkit: missing common table expression")
+	}
+	var (
+		exprs []string
+		args  []interface{}
+	)
+	for _, cte := range w.CTEs {
+		if cte.Name == "" {
+			return "", nil, errors.New("sqkit: missing common table expression name")
+		}
+		query, cteArgs, err := cte.Subquery.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		name := cte.Name
+		if len(cte.Columns) > 0 {
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(cte.Columns, ", "))
+		}
+		exprs = append(exprs, fmt.Sprintf("%s AS (%s)", name, query))
+		args = append(args, cteArgs...)
+	}
+	clause := "WITH "
+	if w.Recursive {
+		clause = "WITH RECURSIVE "
+	}
+	return clause + strings.Join(exprs, ", "), args, nil
+}
+
+// CompileSelect to compile select query for common table expressions
+func (w *With) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	return base.PrefixExpr(w)
+}
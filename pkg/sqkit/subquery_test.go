@@ -0,0 +1,114 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestSubquery(t *testing.T) {
+	authors := sqkit.Subquery{
+		Builder: sq.Select("id").From("authors").PlaceholderFormat(sq.Dollar),
+		Options: []sqkit.SelectOption{sqkit.Eq{"country": "ID"}},
+	}
+	testcases := []struct {
+		testName      string
+		opt           sqkit.SelectOption
+		expectedQuery string
+		expectedArgs  []interface{}
+		expectedErr   string
+	}{
+		{
+			testName:      "in",
+			opt:           &sqkit.In{Column: "author_id", Subquery: authors},
+			expectedQuery: "SELECT title FROM books WHERE year = $1 AND author_id IN (SELECT id FROM authors WHERE country = $2)",
+			expectedArgs:  []interface{}{2020, "ID"},
+		},
+		{
+			testName:      "not in",
+			opt:           &sqkit.In{Column: "author_id", Not: true, Subquery: authors},
+			expectedQuery: "SELECT title FROM books WHERE year = $1 AND author_id NOT IN (SELECT id FROM authors WHERE country = $2)",
+			expectedArgs:  []interface{}{2020, "ID"},
+		},
+		{
+			testName:    "in without column",
+			opt:         &sqkit.In{Subquery: authors},
+			expectedErr: "sqkit: missing subquery column",
+		},
+		{
+			testName: "exists",
+			opt: &sqkit.Exists{Subquery: sqkit.Subquery{
+				Builder: sq.Select("1").From("reviews"),
+				Options: []sqkit.SelectOption{
+					sqkit.Where{"reviews.book_id = books.id"},
+					sqkit.Where{sq.Gt{"rating": 3}},
+				},
+			}},
+			expectedQuery: "SELECT title FROM books WHERE year = $1 AND EXISTS (SELECT 1 FROM reviews WHERE reviews.book_id = books.id AND rating > $2)",
+			expectedArgs:  []interface{}{2020, 3},
+		},
+		{
+			testName:      "not exists",
+			opt:           &sqkit.Exists{Not: true, Subquery: sqkit.Subquery{Builder: sq.Select("1").From("reviews")}},
+			expectedQuery: "SELECT title FROM books WHERE year = $1 AND NOT EXISTS (SELECT 1 FROM reviews)",
+			expectedArgs:  []interface{}{2020},
+		},
+		{
+			testName: "with",
+			opt: &sqkit.With{CTEs: []sqkit.CTE{
+				{Name: "local_authors", Subquery: authors},
+				{
+					Name:     "top_books",
+					Columns:  []string{"book_id"},
+					Subquery: sqkit.Subquery{Builder: sq.Select("book_id").From("reviews").Where(sq.Gt{"rating": 3})},
+				},
+			}},
+			expectedQuery: "WITH local_authors AS (SELECT id FROM authors WHERE country = $1), " +
+				"top_books (book_id) AS (SELECT book_id FROM reviews WHERE rating > $2) " +
+				"SELECT title FROM books WHERE year = $3",
+			expectedArgs: []interface{}{"ID", 3, 2020},
+		},
+		{
+			testName:      "with recursive",
+			opt:           &sqkit.With{Recursive: true, CTEs: []sqkit.CTE{{Name: "t", Subquery: sqkit.Subquery{Builder: sq.Select("1")}}}},
+			expectedQuery: "WITH RECURSIVE t AS (SELECT 1) SELECT title FROM books WHERE year = $1",
+			expectedArgs:  []interface{}{2020},
+		},
+		{
+			testName:    "with without cte",
+			opt:         &sqkit.With{},
+			expectedErr: "sqkit: missing common table expression",
+		},
+		{
+			testName:    "with without name",
+			opt:         &sqkit.With{CTEs: []sqkit.CTE{{Subquery: authors}}},
+			expectedErr: "sqkit: missing common table expression name",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := sq.Select("title").From("books").Where(sq.Eq{"year": 2020}).PlaceholderFormat(sq.Dollar)
+			query, args, err := tt.opt.CompileSelect(builder).ToSql()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestIn_CompileDelete(t *testing.T) {
+	in := &sqkit.In{
+		Column:   "author_id",
+		Subquery: sqkit.Subquery{Builder: sq.Select("id").From("authors").Where(sq.Eq{"active": false})},
+	}
+	query, args, err := in.CompileDelete(sq.Delete("books").PlaceholderFormat(sq.Dollar)).ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "DELETE FROM books WHERE author_id IN (SELECT id FROM authors WHERE active = $1)", query)
+	require.Equal(t, []interface{}{false}, args)
+}
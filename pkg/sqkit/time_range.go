@@ -0,0 +1,200 @@
+package sqkit
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// TimeRange filter time column within range which is inclusive From and
+	// exclusive To by default. Zero From or To is unbounded.
+	TimeRange struct {
+		Column        string
+		From          time.Time
+		To            time.Time
+		ExclusiveFrom bool
+		InclusiveTo   bool
+		Location      *time.Location // timezone of the column value, by default is UTC
+	}
+	// DateTrunc select and group by the time column truncated to the unit
+	// (e.g. `date_trunc('day', created_at) AS bucket`)
+	DateTrunc struct {
+		Dialect  Dialect
+		Column   string
+		Unit     TimeUnit
+		Location *time.Location // timezone of the bucket, by default is UTC
+		Alias    string         // by default is `bucket`
+	}
+	// TimeUnit of date truncation
+	TimeUnit string
+)
+
+const (
+	// Minute time unit
+	Minute TimeUnit = "minute"
+	// Hour time unit
+	Hour TimeUnit = "hour"
+	// Day time unit
+	Day TimeUnit = "day"
+	// Week time unit which start on monday
+	Week TimeUnit = "week"
+	// Month time unit
+	Month TimeUnit = "month"
+	// Year time unit
+	Year TimeUnit = "year"
+)
+
+var _ SelectOption = (*TimeRange)(nil)
+var _ UpdateOption = (*TimeRange)(nil)
+var _ DeleteOption = (*TimeRange)(nil)
+var _ SelectOption = (*DateTrunc)(nil)
+
+var relativeRange = regexp.MustCompile(`^(?:last\s+)?(\d+)\s*(m|h|d|w)$`)
+
+var mysqlDateFormats = map[TimeUnit]string{
+	Minute: "%Y-%m-%d %H:%i:00",
+	Hour:   "%Y-%m-%d %H:00:00",
+	Day:    "%Y-%m-%d",
+	Month:  "%Y-%m-01",
+	Year:   "%Y-01-01",
+}
+
+// ParseRelativeRange parse relative time range until now (e.g. `7d` or `last 7d`)
+// with unit `m` (minute), `h` (hour), `d` (day) or `w` (week)
+func ParseRelativeRange(column, raw string, now time.Time) (*TimeRange, error) {
+	match := relativeRange.FindStringSubmatch(strings.ToLower(strings.TrimSpace(raw)))
+	if match == nil {
+		return nil, fmt.Errorf("sqkit: invalid relative range '%s'", raw)
+	}
+	n, _ := strconv.Atoi(match[1])
+	var unit time.Duration
+	switch match[2] {
+	case "m":
+		unit = time.Minute
+	case "h":
+		unit = time.Hour
+	case "d":
+		unit = 24 * time.Hour
+	case "w":
+		unit = 7 * 24 * time.Hour
+	}
+	return &TimeRange{
+		Column:      column,
+		From:        now.Add(-time.Duration(n) * unit),
+		To:          now,
+		InclusiveTo: true,
+	}, nil
+}
+
+//
+// TimeRange
+//
+
+// ToSql return time range condition
+func (t *TimeRange) ToSql() (string, []interface{}, error) {
+	var conds sq.And
+	if !t.From.IsZero() {
+		if t.ExclusiveFrom {
+			conds = append(conds, sq.Gt{t.Column: t.in(t.From)})
+		} else {
+			conds = append(conds, sq.GtOrEq{t.Column: t.in(t.From)})
+		}
+	}
+	if !t.To.IsZero() {
+		if t.InclusiveTo {
+			conds = append(conds, sq.LtOrEq{t.Column: t.in(t.To)})
+		} else {
+			conds = append(conds, sq.Lt{t.Column: t.in(t.To)})
+		}
+	}
+	return conds.ToSql()
+}
+
+// CompileSelect to compile select query for time range
+func (t *TimeRange) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	if t.From.IsZero() && t.To.IsZero() {
+		return base
+	}
+	return base.Where(t)
+}
+
+// CompileUpdate to compile update query for time range
+func (t *TimeRange) CompileUpdate(base sq.UpdateBuilder) sq.UpdateBuilder {
+	if t.From.IsZero() && t.To.IsZero() {
+		return base
+	}
+	return base.Where(t)
+}
+
+// CompileDelete to compile delete query for time range
+func (t *TimeRange) CompileDelete(base sq.DeleteBuilder) sq.DeleteBuilder {
+	if t.From.IsZero() && t.To.IsZero() {
+		return base
+	}
+	return base.Where(t)
+}
+
+func (t *TimeRange) in(tm time.Time) time.Time {
+	if t.Location == nil {
+		return tm.UTC()
+	}
+	return tm.In(t.Location)
+}
+
+//
+// DateTrunc
+//
+
+// CompileSelect to compile select query for date bucketing
+func (d *DateTrunc) CompileSelect(base sq.SelectBuilder) sq.SelectBuilder {
+	expr, err := d.Expr()
+	if err != nil {
+		return base.Where(errSqlizer{err: err})
+	}
+	alias := d.Alias
+	if alias == "" {
+		alias = "bucket"
+	}
+	return base.Column(fmt.Sprintf("%s AS %s", expr, alias)).GroupBy(expr)
+}
+
+// Expr return truncated time expression without alias. The column value is
+// expected in UTC.
+func (d *DateTrunc) Expr() (string, error) {
+	column := d.Column
+	switch d.Dialect {
+	case Postgres:
+		switch d.Unit {
+		case Minute, Hour, Day, Week, Month, Year:
+		default:
+			return "", fmt.Errorf("sqkit: unknown time unit '%s'", d.Unit)
+		}
+		if d.Location != nil && d.Location != time.UTC {
+			column = fmt.Sprintf("%s AT TIME ZONE 'UTC' AT TIME ZONE %s", column, quoteLiteral(d.Location.String()))
+		}
+		return fmt.Sprintf("date_trunc('%s', %s)", d.Unit, column), nil
+	case MySQL:
+		if d.Location != nil && d.Location != time.UTC {
+			// require timezone tables to be loaded in mysql
+			column = fmt.Sprintf("CONVERT_TZ(%s, '+00:00', %s)", column, quoteLiteral(d.Location.String()))
+		}
+		if d.Unit == Week {
+			return fmt.Sprintf("DATE_SUB(DATE(%s), INTERVAL WEEKDAY(%s) DAY)", column, column), nil
+		}
+		format, ok := mysqlDateFormats[d.Unit]
+		if !ok {
+			return "", fmt.Errorf("sqkit: unknown time unit '%s'", d.Unit)
+		}
+		return fmt.Sprintf("DATE_FORMAT(%s, '%s')", column, format), nil
+	}
+	return "", fmt.Errorf("sqkit: date trunc not support dialect '%s'", d.Dialect)
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
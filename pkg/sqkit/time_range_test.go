@@ -0,0 +1,173 @@
+package sqkit_test
+
+import (
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestTimeRange(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	from := time.Date(2020, 1, 1, 7, 0, 0, 0, jakarta)
+	to := time.Date(2020, 2, 1, 7, 0, 0, 0, jakarta)
+	testcases := []struct {
+		testName string
+		*sqkit.TimeRange
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			testName:      "unbounded",
+			TimeRange:     &sqkit.TimeRange{Column: "created_at"},
+			expectedQuery: "SELECT id FROM books",
+		},
+		{
+			testName:      "inclusive from and exclusive to",
+			TimeRange:     &sqkit.TimeRange{Column: "created_at", From: from, To: to},
+			expectedQuery: "SELECT id FROM books WHERE (created_at >= ? AND created_at < ?)",
+			expectedArgs:  []interface{}{from.UTC(), to.UTC()},
+		},
+		{
+			testName:      "exclusive from and inclusive to",
+			TimeRange:     &sqkit.TimeRange{Column: "created_at", From: from, To: to, ExclusiveFrom: true, InclusiveTo: true},
+			expectedQuery: "SELECT id FROM books WHERE (created_at > ? AND created_at <= ?)",
+			expectedArgs:  []interface{}{from.UTC(), to.UTC()},
+		},
+		{
+			testName:      "from only in location",
+			TimeRange:     &sqkit.TimeRange{Column: "created_at", From: from.UTC(), Location: jakarta},
+			expectedQuery: "SELECT id FROM books WHERE (created_at >= ?)",
+			expectedArgs:  []interface{}{from},
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			query, args, err := tt.CompileSelect(sq.Select("id").From("books")).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestTimeRange_CompileDelete(t *testing.T) {
+	to := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	query, args, err := (&sqkit.TimeRange{Column: "created_at", To: to}).
+		CompileDelete(sq.Delete("logs")).
+		ToSql()
+	require.NoError(t, err)
+	require.Equal(t, "DELETE FROM logs WHERE (created_at < ?)", query)
+	require.Equal(t, []interface{}{to}, args)
+}
+
+func TestParseRelativeRange(t *testing.T) {
+	now := time.Date(2020, 1, 10, 12, 0, 0, 0, time.UTC)
+	testcases := []struct {
+		raw         string
+		expected    *sqkit.TimeRange
+		expectedErr string
+	}{
+		{
+			raw: "7d",
+			expected: &sqkit.TimeRange{
+				Column:      "created_at",
+				From:        time.Date(2020, 1, 3, 12, 0, 0, 0, time.UTC),
+				To:          now,
+				InclusiveTo: true,
+			},
+		},
+		{
+			raw: "last 2w",
+			expected: &sqkit.TimeRange{
+				Column:      "created_at",
+				From:        time.Date(2019, 12, 27, 12, 0, 0, 0, time.UTC),
+				To:          now,
+				InclusiveTo: true,
+			},
+		},
+		{
+			raw: "30m",
+			expected: &sqkit.TimeRange{
+				Column:      "created_at",
+				From:        time.Date(2020, 1, 10, 11, 30, 0, 0, time.UTC),
+				To:          now,
+				InclusiveTo: true,
+			},
+		},
+		{
+			raw:         "7y",
+			expectedErr: "sqkit: invalid relative range '7y'",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.raw, func(t *testing.T) {
+			timeRange, err := sqkit.ParseRelativeRange("created_at", tt.raw, now)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, timeRange)
+		})
+	}
+}
+
+func TestDateTrunc(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	testcases := []struct {
+		testName string
+		*sqkit.DateTrunc
+		expectedQuery string
+		expectedErr   string
+	}{
+		{
+			testName:      "postgres",
+			DateTrunc:     &sqkit.DateTrunc{Dialect: sqkit.Postgres, Column: "created_at", Unit: sqkit.Day},
+			expectedQuery: "SELECT date_trunc('day', created_at) AS bucket, count(*) FROM books GROUP BY date_trunc('day', created_at)",
+		},
+		{
+			testName:  "postgres with location",
+			DateTrunc: &sqkit.DateTrunc{Dialect: sqkit.Postgres, Column: "created_at", Unit: sqkit.Month, Location: jakarta, Alias: "month"},
+			expectedQuery: "SELECT date_trunc('month', created_at AT TIME ZONE 'UTC' AT TIME ZONE 'Asia/Jakarta') AS month, count(*) FROM books " +
+				"GROUP BY date_trunc('month', created_at AT TIME ZONE 'UTC' AT TIME ZONE 'Asia/Jakarta')",
+		},
+		{
+			testName:      "mysql",
+			DateTrunc:     &sqkit.DateTrunc{Dialect: sqkit.MySQL, Column: "created_at", Unit: sqkit.Hour},
+			expectedQuery: "SELECT DATE_FORMAT(created_at, '%Y-%m-%d %H:00:00') AS bucket, count(*) FROM books GROUP BY DATE_FORMAT(created_at, '%Y-%m-%d %H:00:00')",
+		},
+		{
+			testName:  "mysql week with location",
+			DateTrunc: &sqkit.DateTrunc{Dialect: sqkit.MySQL, Column: "created_at", Unit: sqkit.Week, Location: jakarta},
+			expectedQuery: "SELECT DATE_SUB(DATE(CONVERT_TZ(created_at, '+00:00', 'Asia/Jakarta')), INTERVAL WEEKDAY(CONVERT_TZ(created_at, '+00:00', 'Asia/Jakarta')) DAY) AS bucket, count(*) FROM books " +
+				"GROUP BY DATE_SUB(DATE(CONVERT_TZ(created_at, '+00:00', 'Asia/Jakarta')), INTERVAL WEEKDAY(CONVERT_TZ(created_at, '+00:00', 'Asia/Jakarta')) DAY)",
+		},
+		{
+			testName:    "unknown unit",
+			DateTrunc:   &sqkit.DateTrunc{Dialect: sqkit.Postgres, Column: "created_at", Unit: "century"},
+			expectedErr: "sqkit: unknown time unit 'century'",
+		},
+		{
+			testName:    "unknown dialect",
+			DateTrunc:   &sqkit.DateTrunc{Column: "created_at", Unit: sqkit.Day},
+			expectedErr: "sqkit: date trunc not support dialect ''",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := tt.CompileSelect(sq.Select().From("books"))
+			builder = (&sqkit.Aggregate{Func: sqkit.Count}).CompileSelect(builder)
+			query, _, err := builder.ToSql()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+		})
+	}
+}
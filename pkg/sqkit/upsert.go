@@ -0,0 +1,57 @@
+package sqkit
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type (
+	// OnConflict is postgres upsert. Compile to `ON CONFLICT (columns) DO
+	// UPDATE SET column = EXCLUDED.column` or `DO NOTHING` when no update
+	// column. Insert with `RETURNING` return no row when do nothing so the
+	// generated Insert return 0 id.
+	OnConflict struct {
+		Columns []string
+		Update  []string
+	}
+	// OnDuplicateKey is mysql upsert. Compile to `ON DUPLICATE KEY UPDATE
+	// column = VALUES(column)` or `INSERT IGNORE` when no update column.
+	// LastInsertId (the id of generated Insert) is 0 when the row is updated
+	// or ignored instead of inserted.
+	OnDuplicateKey struct {
+		Update []string
+	}
+)
+
+var _ InsertOption = (*OnConflict)(nil)
+var _ InsertOption = (*OnDuplicateKey)(nil)
+
+// CompileInsert to compile insert query for upsert
+func (o *OnConflict) CompileInsert(base sq.InsertBuilder) sq.InsertBuilder {
+	var target string
+	if len(o.Columns) > 0 {
+		target = fmt.Sprintf(" (%s)", strings.Join(o.Columns, ", "))
+	}
+	if len(o.Update) < 1 {
+		return base.Suffix(fmt.Sprintf("ON CONFLICT%s DO NOTHING", target))
+	}
+	sets := make([]string, len(o.Update))
+	for i, column := range o.Update {
+		sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", column, column)
+	}
+	return base.Suffix(fmt.Sprintf("ON CONFLICT%s DO UPDATE SET %s", target, strings.Join(sets, ", ")))
+}
+
+// CompileInsert to compile insert query for upsert
+func (o *OnDuplicateKey) CompileInsert(base sq.InsertBuilder) sq.InsertBuilder {
+	if len(o.Update) < 1 {
+		return base.Options("IGNORE")
+	}
+	sets := make([]string, len(o.Update))
+	for i, column := range o.Update {
+		sets[i] = fmt.Sprintf("%s = VALUES(%s)", column, column)
+	}
+	return base.Suffix("ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", "))
+}
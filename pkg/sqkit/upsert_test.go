@@ -0,0 +1,51 @@
+package sqkit_test
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-rest-server/pkg/sqkit"
+)
+
+func TestUpsert(t *testing.T) {
+	testcases := []struct {
+		testName      string
+		opt           sqkit.InsertOption
+		expectedQuery string
+	}{
+		{
+			testName:      "postgres do nothing",
+			opt:           &sqkit.OnConflict{},
+			expectedQuery: "INSERT INTO books (isbn,title) VALUES (?,?) ON CONFLICT DO NOTHING",
+		},
+		{
+			testName:      "postgres do nothing on columns",
+			opt:           &sqkit.OnConflict{Columns: []string{"isbn"}},
+			expectedQuery: "INSERT INTO books (isbn,title) VALUES (?,?) ON CONFLICT (isbn) DO NOTHING",
+		},
+		{
+			testName:      "postgres do update",
+			opt:           &sqkit.OnConflict{Columns: []string{"isbn"}, Update: []string{"title", "author"}},
+			expectedQuery: "INSERT INTO books (isbn,title) VALUES (?,?) ON CONFLICT (isbn) DO UPDATE SET title = EXCLUDED.title, author = EXCLUDED.author",
+		},
+		{
+			testName:      "mysql ignore",
+			opt:           &sqkit.OnDuplicateKey{},
+			expectedQuery: "INSERT IGNORE INTO books (isbn,title) VALUES (?,?)",
+		},
+		{
+			testName:      "mysql update",
+			opt:           &sqkit.OnDuplicateKey{Update: []string{"title", "author"}},
+			expectedQuery: "INSERT INTO books (isbn,title) VALUES (?,?) ON DUPLICATE KEY UPDATE title = VALUES(title), author = VALUES(author)",
+		},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.testName, func(t *testing.T) {
+			builder := sq.Insert("books").Columns("isbn", "title").Values("some-isbn", "some-title")
+			query, _, err := tt.opt.CompileInsert(builder).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedQuery, query)
+		})
+	}
+}
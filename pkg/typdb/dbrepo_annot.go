@@ -30,6 +30,7 @@ type (
 		Fields     []*Field
 		Imports    map[string]string
 		PrimaryKey *Field
+		SoftDelete *Field
 	}
 	// Field repo
 	Field struct {
@@ -39,15 +40,17 @@ type (
 		PrimaryKey   bool
 		DefaultValue string
 		SkipUpdate   bool
+		SoftDelete   bool
 	}
 	fieldOptions []string
 )
 
 const (
-	pkOpt       = "pk"
-	nowOpt      = "now"
-	noUpdateOpt = "no_update"
-	parentDest  = "internal/generated/dbrepo"
+	pkOpt         = "pk"
+	nowOpt        = "now"
+	noUpdateOpt   = "no_update"
+	softDeleteOpt = "soft_delete"
+	parentDest    = "internal/generated/dbrepo"
 )
 
 //
@@ -75,7 +78,7 @@ func (m *DBRepoAnnot) Annotation() *typgen.Annotation {
 func (m *DBRepoAnnot) process(c *typgo.Context, directive typgen.Directives) error {
 	os.RemoveAll(parentDest)
 	for _, directive := range directive {
-		ent, err := m.createEntity(directive)
+		ent, err := m.CreateEntity(directive)
 		if err != nil {
 			return err
 		}
@@ -134,7 +137,7 @@ func (m *DBRepoAnnot) getTagName() string {
 //
 
 // CreateEntity create entity
-func (m *DBRepoAnnot) createEntity(directive *typgen.Directive) (*EntityTmplData, error) {
+func (m *DBRepoAnnot) CreateEntity(directive *typgen.Directive) (*EntityTmplData, error) {
 	name := directive.GetName()
 	table := directive.TagParam.Get("table")
 
@@ -152,7 +155,7 @@ func (m *DBRepoAnnot) createEntity(directive *typgen.Directive) (*EntityTmplData
 	dest := m.GetDest(directive.Path)
 	pkg := filepath.Base(dest)
 	sourcePkg := filepath.Base(filepath.Dir(directive.Path))
-	fields, primaryKey := m.createFields(directive)
+	fields, primaryKey, softDelete := m.createFields(directive)
 
 	imports := map[string]string{
 		"context":                         "",
@@ -179,6 +182,7 @@ func (m *DBRepoAnnot) createEntity(directive *typgen.Directive) (*EntityTmplData
 		Dest:       dest,
 		Fields:     fields,
 		PrimaryKey: primaryKey,
+		SoftDelete: softDelete,
 		Imports:    imports,
 	}, nil
 }
@@ -197,7 +201,7 @@ func (*DBRepoAnnot) GetDest(file string) string {
 	return fmt.Sprintf("%s/%s_repo", parentDest, source)
 }
 
-func (m *DBRepoAnnot) createFields(directive *typgen.Directive) (fields []*Field, primaryKey *Field) {
+func (m *DBRepoAnnot) createFields(directive *typgen.Directive) (fields []*Field, primaryKey, softDelete *Field) {
 	structDecl := directive.Decl.Type.(*typgen.StructDecl)
 	for _, f := range structDecl.Fields {
 		name := f.Names[0]
@@ -212,12 +216,16 @@ func (m *DBRepoAnnot) createFields(directive *typgen.Directive) (fields []*Field
 			Column:       column,
 			PrimaryKey:   opts.primaryKey(),
 			DefaultValue: opts.defaultValue(),
-			SkipUpdate:   opts.skipUpdate(),
+			SkipUpdate:   opts.skipUpdate() || opts.softDelete(),
+			SoftDelete:   opts.softDelete(),
 		}
 		fields = append(fields, field)
 		if field.PrimaryKey {
 			primaryKey = field
 		}
+		if field.SoftDelete {
+			softDelete = field
+		}
 	}
 	return
 }
@@ -253,3 +261,12 @@ func (o fieldOptions) skipUpdate() bool {
 	}
 	return false
 }
+
+func (o fieldOptions) softDelete() bool {
+	for _, opt := range o {
+		if strings.EqualFold(opt, softDeleteOpt) {
+			return true
+		}
+	}
+	return false
+}
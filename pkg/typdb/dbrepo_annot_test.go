@@ -1,9 +1,17 @@
 package typdb_test
 
 import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/typical-go/typical-go/pkg/typgen"
+	"github.com/typical-go/typical-go/pkg/typgo"
 	"github.com/typical-go/typical-rest-server/pkg/typdb"
 )
 
@@ -24,3 +32,85 @@ func TestGetDest(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateEntity_Fields(t *testing.T) {
+	a := typdb.DBRepoAnnot{}
+	ent, err := a.CreateEntity(bookDirective("postgres"))
+	require.NoError(t, err)
+
+	require.Equal(t, []*typdb.Field{
+		{Name: "ID", Type: "int64", Column: "id", PrimaryKey: true},
+		{Name: "Title", Type: "string", Column: "title"},
+		{Name: "CreatedAt", Type: "time.Time", Column: "created_at", DefaultValue: "time.Now()"},
+		{Name: "DeletedAt", Type: "*time.Time", Column: "deleted_at", SkipUpdate: true, SoftDelete: true},
+	}, ent.Fields)
+	require.Equal(t, ent.Fields[0], ent.PrimaryKey)
+	require.Equal(t, ent.Fields[3], ent.SoftDelete)
+}
+
+func TestDBRepoAnnot_Process_SoftDelete(t *testing.T) {
+	typgo.ProjectPkg = "github.com/user/project"
+	defer os.RemoveAll("internal")
+
+	for _, dialect := range []string{"postgres", "mysql"} {
+		t.Run(dialect, func(t *testing.T) {
+			a := &typdb.DBRepoAnnot{}
+			var out strings.Builder
+			c := &typgo.Context{Logger: typgo.Logger{Stdout: &out}}
+			defer c.PatchBash([]*typgo.MockBash{})(t)
+
+			require.NoError(t, a.Process(c, []*typgen.Directive{bookDirective(dialect)}))
+
+			b, err := ioutil.ReadFile("internal/generated/dbrepo/book_repo.go")
+			require.NoError(t, err)
+			_, err = parser.ParseFile(token.NewFileSet(), "book_repo.go", b, 0)
+			require.NoError(t, err)
+
+			code := string(b)
+			require.Equal(t, 3, strings.Count(code, `opts = sqkit.WithSoftDelete(BookTableName+"."+BookTable.DeletedAt, opts)`))
+			require.Equal(t, 2, strings.Count(code, `builder = (&sqkit.SoftDelete{Column: BookTable.DeletedAt}).CompileUpdate(builder)`))
+			require.Contains(t, code, `builder, err := (&sqkit.SoftDelete{Column: BookTable.DeletedAt}).`)
+		})
+	}
+}
+
+func TestDBRepoAnnot_Process_WithoutPrimaryKey(t *testing.T) {
+	typgo.ProjectPkg = "github.com/user/project"
+	defer os.RemoveAll("internal")
+
+	// NOTE: postgres require primary key for `RETURNING` of insert
+	directive := bookDirective("mysql")
+	structDecl := directive.Type.(*typgen.StructDecl)
+	structDecl.Fields[0].StructTag = `column:"id"`
+
+	a := &typdb.DBRepoAnnot{}
+	var out strings.Builder
+	c := &typgo.Context{Logger: typgo.Logger{Stdout: &out}}
+	defer c.PatchBash([]*typgo.MockBash{})(t)
+
+	require.NoError(t, a.Process(c, []*typgen.Directive{directive}))
+	require.NotContains(t, out.String(), "WARN")
+
+	b, err := ioutil.ReadFile("internal/generated/dbrepo/book_repo.go")
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "FindByID")
+}
+
+func bookDirective(dialect string) *typgen.Directive {
+	return &typgen.Directive{
+		TagName:  "@dbrepo",
+		TagParam: reflect.StructTag(`table:"books" dialect:"` + dialect + `"`),
+		Decl: &typgen.Decl{
+			File: typgen.File{Package: "entity", Path: "internal/app/entity/book.go"},
+			Type: &typgen.StructDecl{
+				TypeDecl: typgen.TypeDecl{Name: "Book"},
+				Fields: []*typgen.Field{
+					{Names: []string{"ID"}, Type: "int64", StructTag: `column:"id" option:"pk"`},
+					{Names: []string{"Title"}, Type: "string", StructTag: `column:"title"`},
+					{Names: []string{"CreatedAt"}, Type: "time.Time", StructTag: `column:"created_at" option:"now"`},
+					{Names: []string{"DeletedAt"}, Type: "*time.Time", StructTag: `column:"deleted_at" option:"soft_delete"`},
+				},
+			},
+		},
+	}
+}
@@ -26,8 +26,11 @@ type (
 	{{.Name}}Repo interface {
 		Count(context.Context, ...sqkit.SelectOption) (int64, error)
 		Find(context.Context, ...sqkit.SelectOption) ([]*{{.SourcePkg}}.{{.Name}}, error)
-		Insert(context.Context, *{{.SourcePkg}}.{{.Name}}) (int64, error)
-		BulkInsert(context.Context, ...*{{.SourcePkg}}.{{.Name}}) (int64, error)
+		FindOne(context.Context, ...sqkit.SelectOption) (*{{.SourcePkg}}.{{.Name}}, error)
+		{{if .PrimaryKey}}FindByID(context.Context, {{.PrimaryKey.Type}}) (*{{.SourcePkg}}.{{.Name}}, error)
+		{{end}}Exists(context.Context, ...sqkit.SelectOption) (bool, error)
+		Insert(context.Context, *{{.SourcePkg}}.{{.Name}}, ...sqkit.InsertOption) (int64, error)
+		BulkInsert(context.Context, []*{{.SourcePkg}}.{{.Name}}, ...sqkit.InsertOption) (int64, error)
 		Delete(context.Context, sqkit.DeleteOption) (int64, error)
 		Update(context.Context, *{{.SourcePkg}}.{{.Name}}, sqkit.UpdateOption) (int64, error)
 		Patch(context.Context, *{{.SourcePkg}}.{{.Name}}, sqkit.UpdateOption) (int64, error)
@@ -50,7 +53,7 @@ func New{{.Name}}Repo(impl {{.Name}}RepoImpl) {{.Name}}Repo {
 
 // Count {{.Table}}
 func (r *{{.Name}}RepoImpl) Count(ctx context.Context, opts ...sqkit.SelectOption) (int64, error) {
-	txn, err := dbtxn.Use(ctx, r.DB)
+	txn, err := dbtxn.UseRead(ctx, r.DB)
 	if err != nil {
 		return -1, err
 	}
@@ -59,7 +62,8 @@ func (r *{{.Name}}RepoImpl) Count(ctx context.Context, opts ...sqkit.SelectOptio
 		From({{.Name}}TableName).
 		RunWith(txn)
 
-	for _, opt := range opts {
+	{{if .SoftDelete}}opts = sqkit.WithSoftDelete({{.Name}}TableName+"."+{{.Name}}Table.{{.SoftDelete.Name}}, opts)
+	{{end}}for _, opt := range opts {
 		builder = opt.CompileSelect(builder)
 	}
 
@@ -75,19 +79,23 @@ func (r *{{.Name}}RepoImpl) Count(ctx context.Context, opts ...sqkit.SelectOptio
 
 // Find {{.Table}}
 func (r *{{.Name}}RepoImpl) Find(ctx context.Context, opts ...sqkit.SelectOption) (list []*{{.SourcePkg}}.{{.Name}}, err error) {
-	txn, err := dbtxn.Use(ctx, r.DB)
+	if sqkit.HasLock(opts) && dbtxn.Find(ctx) == nil {
+		return nil, sqkit.ErrLockWithoutTxn
+	}
+	txn, err := dbtxn.UseRead(ctx, r.DB)
 	if err != nil {
 		return nil, err
 	}
 	builder := sq.
-		Select(
+		Select(sqkit.Qualify({{.Name}}TableName, sqkit.SelectColumns([]string{
 			{{range .Fields}}{{$.Name}}Table.{{.Name}},
-			{{end}}
-		).
+			{{end}}}, opts)...)...).
+		Columns(sqkit.SelectExtra(opts)...).
 		From({{.Name}}TableName).
 		RunWith(txn)
 
-	for _, opt := range opts {
+	{{if .SoftDelete}}opts = sqkit.WithSoftDelete({{.Name}}TableName+"."+{{.Name}}Table.{{.SoftDelete.Name}}, opts)
+	{{end}}for _, opt := range opts {
 		builder = opt.CompileSelect(builder)
 	}
 
@@ -96,21 +104,81 @@ func (r *{{.Name}}RepoImpl) Find(ctx context.Context, opts ...sqkit.SelectOption
 		return
 	}
 
+	columns, err := rows.Columns()
+	if err != nil {
+		return
+	}
+
 	list = make([]*{{.SourcePkg}}.{{.Name}}, 0)
 	for rows.Next() {
 		ent := new({{.SourcePkg}}.{{.Name}})
-		if err = rows.Scan({{range .Fields}}
-			&ent.{{.Name}},{{end}}
-		); err != nil {
+		dest := make([]interface{}, len(columns))
+		extra := make(map[string]interface{})
+		for i, column := range columns {
+			switch column {
+			{{range .Fields}}case {{$.Name}}Table.{{.Name}}:
+				dest[i] = &ent.{{.Name}}
+			{{end}}default:
+				dest[i] = new(interface{})
+				extra[column] = dest[i]
+			}
+		}
+		if err = rows.Scan(dest...); err != nil {
 			return
 		}
+		sqkit.ScanExtra(opts, extra)
 		list = append(list, ent)
 	}
 	return
 }
 
+// FindOne {{.Table}} or return sqkit.NotFoundError
+func (r *{{.Name}}RepoImpl) FindOne(ctx context.Context, opts ...sqkit.SelectOption) (*{{.SourcePkg}}.{{.Name}}, error) {
+	opts = append(opts[:len(opts):len(opts)], &sqkit.OffsetPagination{Limit: 1}) // NOTE: copy to not modify the caller options
+	list, err := r.Find(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) < 1 {
+		return nil, &sqkit.NotFoundError{Table: {{.Name}}TableName}
+	}
+	return list[0], nil
+}
+
+{{if .PrimaryKey}}// FindByID {{.Table}} or return sqkit.NotFoundError
+func (r *{{.Name}}RepoImpl) FindByID(ctx context.Context, id {{.PrimaryKey.Type}}) (*{{.SourcePkg}}.{{.Name}}, error) {
+	return r.FindOne(ctx, sqkit.Eq{ {{.Name}}Table.{{.PrimaryKey.Name}}: id})
+}
+
+{{end}}// Exists return true if any {{.Table}} match the options
+func (r *{{.Name}}RepoImpl) Exists(ctx context.Context, opts ...sqkit.SelectOption) (bool, error) {
+	txn, err := dbtxn.UseRead(ctx, r.DB)
+	if err != nil {
+		return false, err
+	}
+	builder := sq.
+		Select("1").
+		From({{.Name}}TableName).
+		Limit(1).
+		RunWith(txn)
+
+	{{if .SoftDelete}}opts = sqkit.WithSoftDelete({{.Name}}TableName+"."+{{.Name}}Table.{{.SoftDelete.Name}}, opts)
+	{{end}}for _, opt := range opts {
+		builder = opt.CompileSelect(builder)
+	}
+
+	var one int
+	if err := builder.QueryRowContext(ctx).Scan(&one); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // BulkInsert {{.Table}} and return affected row
-func (r *{{.Name}}RepoImpl) BulkInsert(ctx context.Context, ents ...*{{.SourcePkg}}.{{.Name}}) (int64, error) {
+func (r *{{.Name}}RepoImpl) BulkInsert(ctx context.Context, ents []*{{.SourcePkg}}.{{.Name}}, opts ...sqkit.InsertOption) (int64, error) {
 	txn, err := dbtxn.Use(ctx, r.DB)
 	if err != nil {
 		return -1, err
@@ -126,6 +194,10 @@ func (r *{{.Name}}RepoImpl) BulkInsert(ctx context.Context, ents ...*{{.SourcePk
 			{{end}})
 	}
 
+	for _, opt := range opts {
+		builder = opt.CompileInsert(builder)
+	}
+
 	res, err := builder.RunWith(txn).ExecContext(ctx)
 	if err != nil {
 		txn.AppendError(err)
@@ -137,8 +209,9 @@ func (r *{{.Name}}RepoImpl) BulkInsert(ctx context.Context, ents ...*{{.SourcePk
 	return affectedRow, err
 }
 
-// Insert {{.Table}} and return last inserted id
-func (r *{{.Name}}RepoImpl) Insert(ctx context.Context, ent *{{.SourcePkg}}.{{.Name}}) (int64, error) {
+// Insert {{.Table}} and return last inserted id or 0 when nothing inserted
+// or updated (e.g. by sqkit.OnDuplicateKey)
+func (r *{{.Name}}RepoImpl) Insert(ctx context.Context, ent *{{.SourcePkg}}.{{.Name}}, opts ...sqkit.InsertOption) (int64, error) {
 	txn, err := dbtxn.Use(ctx, r.DB)
 	if err != nil {
 		return -1, err
@@ -151,6 +224,10 @@ func (r *{{.Name}}RepoImpl) Insert(ctx context.Context, ent *{{.SourcePkg}}.{{.N
 		Values({{range .Fields}}{{if .DefaultValue}}	{{.DefaultValue}},{{else if not .PrimaryKey}}	ent.{{.Name}},{{end}}
 		{{end}})
 
+	for _, opt := range opts {
+		builder = opt.CompileInsert(builder)
+	}
+
 	res, err := builder.RunWith(txn).ExecContext(ctx)
 	if err != nil {
 		txn.AppendError(err)
@@ -177,7 +254,8 @@ func (r *{{.Name}}RepoImpl) Update(ctx context.Context, ent *{{.SourcePkg}}.{{.N
 
 	if opt != nil {
 		builder = opt.CompileUpdate(builder)
-	}
+	}{{if .SoftDelete}}
+	builder = (&sqkit.SoftDelete{Column: {{.Name}}Table.{{.SoftDelete.Name}}}).CompileUpdate(builder){{end}}
 
 	res, err := builder.ExecContext(ctx)
 	if err != nil {
@@ -205,7 +283,8 @@ func (r *{{.Name}}RepoImpl) Patch(ctx context.Context, ent *{{.SourcePkg}}.{{.Na
 
 	if opt != nil {
 		builder = opt.CompileUpdate(builder)
-	}
+	}{{if .SoftDelete}}
+	builder = (&sqkit.SoftDelete{Column: {{.Name}}Table.{{.SoftDelete.Name}}}).CompileUpdate(builder){{end}}
 
 	res, err := builder.ExecContext(ctx)
 	if err != nil {
@@ -226,10 +305,15 @@ func (r *{{.Name}}RepoImpl) Delete(ctx context.Context, opt sqkit.DeleteOption)
 		return -1, err
 	}
 
-	builder := sq.Delete({{.Name}}TableName).RunWith(txn)
+	{{if .SoftDelete}}builder, err := (&sqkit.SoftDelete{Column: {{.Name}}Table.{{.SoftDelete.Name}}}).
+		Delete({{.Name}}TableName, opt)
+	if err != nil {
+		return -1, err
+	}
+	builder = builder.RunWith(txn){{else}}builder := sq.Delete({{.Name}}TableName).RunWith(txn)
 	if opt != nil {
 		builder = opt.CompileDelete(builder)
-	}
+	}{{end}}
 
 	res, err := builder.ExecContext(ctx)
 	if err != nil {